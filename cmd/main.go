@@ -49,6 +49,15 @@ func main() {
 			hubman.WithManipulator(
 				hubman.WithSignal[models.SceneChanged](),
 				hubman.WithSignal[models.SceneSaved](),
+				hubman.WithSignal[models.SceneCreated](),
+				hubman.WithSignal[models.SceneRenamed](),
+				hubman.WithSignal[models.SceneDeleted](),
+				hubman.WithSignal[models.CueChanged](),
+				hubman.WithSignal[models.UniverseSnapshot](),
+				hubman.WithSignal[models.CurrentScene](),
+				hubman.WithSignal[models.SceneList](),
+				hubman.WithSignal[models.DeviceInfo](),
+				hubman.WithSignal[models.RDMResponder](),
 				hubman.WithChannel(signals),
 			),
 			hubman.WithExecutor(
@@ -58,12 +67,36 @@ func main() {
 
 					return manager.ProcessSetChannel(ctx, cmd)
 				}),
+				hubman.WithCommand(models.SetChannels{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.SetChannels // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessSetChannels(ctx, cmd)
+				}),
+				hubman.WithCommand(models.SetUniverse{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.SetUniverse // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessSetUniverse(ctx, cmd)
+				}),
 				hubman.WithCommand(models.IncrementChannel{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
 					var cmd models.IncrementChannel // json-like api
 					parser(&cmd)                    // enriches your command with data from redis
 
 					return manager.ProcessIncrementChannel(ctx, cmd)
 				}),
+				hubman.WithCommand(models.SetChannel16{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.SetChannel16 // json-like api
+					parser(&cmd)                // enriches your command with data from redis
+
+					return manager.ProcessSetChannel16(ctx, cmd)
+				}),
+				hubman.WithCommand(models.IncrementChannel16{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.IncrementChannel16 // json-like api
+					parser(&cmd)                      // enriches your command with data from redis
+
+					return manager.ProcessIncrementChannel16(ctx, cmd)
+				}),
 				hubman.WithCommand(models.Blackout{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
 					var cmd models.Blackout // json-like api
 					parser(&cmd)            // enriches your command with data from redis
@@ -82,6 +115,102 @@ func main() {
 
 					return manager.ProcessSaveScene(ctx, cmd)
 				}),
+				hubman.WithCommand(models.LearnScene{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.LearnScene // json-like api
+					parser(&cmd)              // enriches your command with data from redis
+
+					return manager.ProcessLearnScene(ctx, cmd)
+				}),
+				hubman.WithCommand(models.CreateScene{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.CreateScene // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessCreateScene(ctx, cmd)
+				}),
+				hubman.WithCommand(models.SaveSceneAs{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.SaveSceneAs // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessSaveSceneAs(ctx, cmd)
+				}),
+				hubman.WithCommand(models.RenameScene{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.RenameScene // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessRenameScene(ctx, cmd)
+				}),
+				hubman.WithCommand(models.DeleteScene{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.DeleteScene // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessDeleteScene(ctx, cmd)
+				}),
+				hubman.WithCommand(models.GetUniverse{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.GetUniverse // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessGetUniverse(ctx, cmd)
+				}),
+				hubman.WithCommand(models.GetCurrentScene{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.GetCurrentScene // json-like api
+					parser(&cmd)                   // enriches your command with data from redis
+
+					return manager.ProcessGetCurrentScene(ctx, cmd)
+				}),
+				hubman.WithCommand(models.ListScenes{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.ListScenes // json-like api
+					parser(&cmd)              // enriches your command with data from redis
+
+					return manager.ProcessListScenes(ctx, cmd)
+				}),
+				hubman.WithCommand(models.RDMDiscover{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.RDMDiscover // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessRDMDiscover(ctx, cmd)
+				}),
+				hubman.WithCommand(models.RDMSetStartAddress{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.RDMSetStartAddress // json-like api
+					parser(&cmd)                      // enriches your command with data from redis
+
+					return manager.ProcessRDMSetStartAddress(ctx, cmd)
+				}),
+				hubman.WithCommand(models.RDMIdentify{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.RDMIdentify // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessRDMIdentify(ctx, cmd)
+				}),
+				hubman.WithCommand(models.StartEffect{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.StartEffect // json-like api
+					parser(&cmd)               // enriches your command with data from redis
+
+					return manager.ProcessStartEffect(ctx, cmd)
+				}),
+				hubman.WithCommand(models.StopEffect{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.StopEffect // json-like api
+					parser(&cmd)              // enriches your command with data from redis
+
+					return manager.ProcessStopEffect(ctx, cmd)
+				}),
+				hubman.WithCommand(models.CueGo{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.CueGo // json-like api
+					parser(&cmd)         // enriches your command with data from redis
+
+					return manager.ProcessCueGo(ctx, cmd)
+				}),
+				hubman.WithCommand(models.CueBack{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.CueBack // json-like api
+					parser(&cmd)           // enriches your command with data from redis
+
+					return manager.ProcessCueBack(ctx, cmd)
+				}),
+				hubman.WithCommand(models.CueGoto{}, func(command core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd models.CueGoto // json-like api
+					parser(&cmd)           // enriches your command with data from redis
+
+					return manager.ProcessCueGoto(ctx, cmd)
+				}),
 			),
 			hubman.WithOnConfigRefresh(func(configuration core.AgentConfiguration) {
 				update, ok := configuration.User.(*device.UserConfig)
@@ -102,5 +231,6 @@ func main() {
 
 	manager.UpdateDevices(ctx, *userConfig)
 	<-app.WaitShutdown()
+	manager.Close(ctx)
 	os.Exit(0)
 }
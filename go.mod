@@ -7,6 +7,7 @@ require (
 	github.com/akualab/dmx v0.0.0-20130922234952-1ec6837faba7
 	github.com/jsimonetti/go-artnet v0.0.0-20240201124026-e4f1b1b169f4
 	github.com/redis/go-redis/v9 v9.5.1
+	github.com/tarm/goserial v0.0.0-20151007205400-b3440c3c6355
 	go.uber.org/zap v1.27.0
 )
 
@@ -25,7 +26,6 @@ require (
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/oapi-codegen/runtime v1.1.1 // indirect
 	github.com/sirupsen/logrus v1.9.3 // indirect
-	github.com/tarm/goserial v0.0.0-20151007205400-b3440c3c6355 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/exp v0.0.0-20240416160154-fe59bbe5cc7f // indirect
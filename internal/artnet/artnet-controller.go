@@ -1,30 +1,75 @@
 package artnet
 
 import (
+	"fmt"
 	"net"
 	"sync"
 
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
 	"github.com/jsimonetti/go-artnet"
+	"github.com/jsimonetti/go-artnet/packet"
+)
+
+const (
+	DefaultBindAddress      = "127.0.0.1"
+	DefaultBroadcastAddress = "2.255.255.255"
 )
 
 var (
-	controller *artnet.Controller
-	once       sync.Once
+	controller       *artnet.Controller
+	controllerConfig device.ArtNetControllerConfig
+	mutex            sync.Mutex
 )
 
 // Function initializes and returns Artnet controller entity
-func NewArtNetController() *artnet.Controller {
+func NewArtNetController(conf device.ArtNetControllerConfig) (*artnet.Controller, error) {
+	bindAddress := conf.BindAddress
+	if bindAddress == "" {
+		bindAddress = DefaultBindAddress
+	}
+	bindIP := net.ParseIP(bindAddress)
+	if bindIP == nil {
+		return nil, fmt.Errorf("invalid ArtNet bind address '%s'", bindAddress)
+	}
+
+	broadcastAddress := conf.BroadcastAddress
+	if broadcastAddress == "" {
+		broadcastAddress = DefaultBroadcastAddress
+	}
+	broadcastIP := net.ParseIP(broadcastAddress)
+	if broadcastIP == nil {
+		return nil, fmt.Errorf("invalid ArtNet broadcast address '%s'", broadcastAddress)
+	}
+
 	log := artnet.NewDefaultLogger()
-	dev := artnet.NewController("ArtNet controller", net.ParseIP("127.0.0.1"), log)
-	dev.Start()
-	return dev
+	dev := artnet.NewController("ArtNet controller", bindIP, log,
+		artnet.BroadcastAddr(net.UDPAddr{IP: broadcastIP, Port: packet.ArtNetPort}))
+	err := dev.Start()
+	if err != nil {
+		return nil, fmt.Errorf("starting ArtNet controller failed: %v", err)
+	}
+	return dev, nil
 }
 
-// Singletone initialization of artnet controller
-func GetArtNetController() *artnet.Controller {
-	once.Do(func() {
-		controller = NewArtNetController()
-	})
+// Function returns shared artnet controller, controller is restarted if its configuration is changed
+func GetArtNetController(conf device.ArtNetControllerConfig) (*artnet.Controller, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
 
-	return controller
-}
\ No newline at end of file
+	if controller != nil && controllerConfig == conf {
+		return controller, nil
+	}
+
+	if controller != nil {
+		controller.Stop()
+		controller = nil
+	}
+
+	dev, err := NewArtNetController(conf)
+	if err != nil {
+		return nil, err
+	}
+	controller = dev
+	controllerConfig = conf
+	return controller, nil
+}
@@ -3,33 +3,76 @@ package artnet
 import (
 	"context"
 	"fmt"
+	"net"
+	"sync"
 	"time"
 
 	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
-	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
 	"git.miem.hse.ru/hubman/hubman-lib/core"
 	"github.com/jsimonetti/go-artnet"
+	"github.com/jsimonetti/go-artnet/packet"
 
 	"go.uber.org/zap"
 )
 
 // Function initializes and returns Artnet device entity
-func NewArtNetDevice(ctx context.Context, signals chan core.Signal, conf device.ArtNetConfig, logger *zap.Logger, checkManager core.CheckRegistry) (device.Device, error) {
+func NewArtNetDevice(ctx context.Context, signals chan core.Signal, conf device.ArtNetConfig, controllerConf device.ArtNetControllerConfig, profiles []device.FixtureProfileConfig, logger *zap.Logger, checkManager core.CheckRegistry, persister *device.Persister) (device.Device, error) {
+	patch, err := device.ReadPatchFromDeviceConfig(profiles, conf.Fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	var dev *artnet.Controller
+	var address *net.UDPAddr
+	var localAddress *net.UDPAddr
+	if conf.IP == "" {
+		dev, err = GetArtNetController(controllerConf)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ip := net.ParseIP(conf.IP)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ArtNet node address '%s'", conf.IP)
+		}
+		address = &net.UDPAddr{IP: ip, Port: packet.ArtNetPort}
+		if controllerConf.BindAddress != "" {
+			localAddress = &net.UDPAddr{IP: net.ParseIP(controllerConf.BindAddress)}
+		}
+	}
+
 	newArtNet := &artnetDevice{
-		BaseDevice: *device.NewBaseDevice(ctx, conf.Alias, conf.NonBlackoutChannels, conf.Scenes, conf.ReconnectInterval, signals, logger, checkManager),
-		net:        uint8(conf.Net),
-		subUni:     uint8(conf.SubUni),
-		dev:        GetArtNetController()}
+		BaseDevice:   *device.NewBaseDevice(ctx, conf.Alias, conf.NonBlackoutChannels, conf.Scenes, patch, conf.ReconnectInterval, conf.FrameRate, signals, logger, checkManager, persister),
+		net:          uint8(conf.Net),
+		subUni:       uint8(conf.SubUni),
+		dev:          dev,
+		address:      address,
+		localAddress: localAddress,
+		conn:         nil,
+		sequence:     0}
+	if conf.KeepAliveInterval <= 0 {
+		conf.KeepAliveInterval = device.DefaultArtNetKeepAliveInterval
+	}
+	newArtNet.KeepAliveInterval = time.Duration(conf.KeepAliveInterval) * time.Millisecond
+
+	newArtNet.AllowSetUniverse = conf.AllowSetUniverse
+	newArtNet.ProtectedChannels = device.ReadProtectedChannelsFromDeviceConfig(conf.ProtectedChannels)
 
 	go newArtNet.reconnect()
+	go newArtNet.RunOutput(newArtNet.WriteFrameToDevice)
 	return newArtNet, nil
 }
 
 type artnetDevice struct {
 	device.BaseDevice
-	net    uint8
-	subUni uint8
-	dev    *artnet.Controller
+	net          uint8
+	subUni       uint8
+	dev          *artnet.Controller // used for nodes discovered by ArtPoll
+	address      *net.UDPAddr       // used for unicast node
+	localAddress *net.UDPAddr
+	conn         *net.UDPConn
+	sequence     uint8
+	ioMutex      sync.Mutex // guards conn and sequence, device mutex is not held during network I/O
 }
 
 // Function reconnects single Artnet device
@@ -52,6 +95,10 @@ func (d *artnetDevice) reconnect() {
 
 // Function checks availability of single Artnet device
 func (d *artnetDevice) checkHealth() {
+	if d.address != nil {
+		return
+	}
+
 	_, ok := d.dev.OutputAddress[artnet.Address{Net: d.net, SubUni: d.subUni}]
 	if ok {
 		return
@@ -62,6 +109,11 @@ func (d *artnetDevice) checkHealth() {
 
 // Function connects to the Artnet device through network
 func (d *artnetDevice) connect() {
+	if d.address != nil {
+		d.connectUnicast()
+		return
+	}
+
 	_, ok := d.dev.OutputAddress[artnet.Address{Net: d.net, SubUni: d.subUni}]
 	if !ok {
 		connCheck := core.NewCheck(
@@ -74,8 +126,7 @@ func (d *artnetDevice) connect() {
 	}
 
 	d.Connected.CompareAndSwap(false, true)
-	d.WriteUniverseToDevice()
-	
+
 	connCheck := core.NewCheck(
 		fmt.Sprintf(device.DeviceDisconnectedCheckLabelFormat, d.Alias),
 		"",
@@ -84,92 +135,83 @@ func (d *artnetDevice) connect() {
 	d.Logger.Info("Connected ArtNet device",  zap.Any("net", d.net), zap.Any("subuni", d.subUni))
 }
 
-// Function sets scene specified in command and updates universe of single Artnet device
-func (d *artnetDevice) SetScene(ctx context.Context, command models.SetScene) error {
-	err := d.BaseDevice.SetScene(ctx, command)
+// Function opens UDP socket to the unicast Artnet node
+func (d *artnetDevice) connectUnicast() {
+	conn, err := net.DialUDP("udp4", d.localAddress, d.address)
 	if err != nil {
-		return err
+		connCheck := core.NewCheck(
+			fmt.Sprintf(device.DeviceDisconnectedCheckLabelFormat, d.Alias),
+			"",
+		)
+		d.CheckManager.RegisterFail(connCheck)
+		d.Logger.Warn("Unable to connect ArtNet device", zap.Any("ip", d.address.IP.String()), zap.Error(err))
+		return
 	}
 
-	for _, channel := range d.CurrentScene.ChannelMap {
-		d.Universe[channel.UniverseChannelID] = byte(channel.Value)
-	}
+	d.ioMutex.Lock()
+	d.conn = conn
+	d.ioMutex.Unlock()
+	d.Connected.CompareAndSwap(false, true)
 
-	d.SaveUniverseToCache(ctx)
-	d.WriteUniverseToDevice()
-	d.CreateSceneChangedSignal()
-	return nil
+	connCheck := core.NewCheck(
+		fmt.Sprintf(device.DeviceDisconnectedCheckLabelFormat, d.Alias),
+		"",
+	)
+	d.CheckManager.RegisterSuccess(connCheck)
+	d.Logger.Info("Connected ArtNet device", zap.Any("ip", d.address.IP.String()), zap.Any("net", d.net), zap.Any("subuni", d.subUni))
 }
 
-// Function sets and writes value to channel of universe specified in command for single Artnet device
-func (d *artnetDevice) SetChannel(ctx context.Context, command models.SetChannel) error {
-	err := d.BaseDevice.SetChannel(ctx, &command)
-	if err != nil {
-		return err
+// Function writes frame to single Artnet device
+func (d *artnetDevice) WriteFrameToDevice(frame [512]byte) error {
+	if !d.Connected.Load() {
+		return fmt.Errorf("no connection to device")
 	}
 
-	err = d.WriteValueToChannel(command)
-	if err != nil {
-		return err
+	if d.address != nil {
+		return d.writeFrameToNode(frame)
 	}
+
+	d.dev.SendDMXToAddress(frame, artnet.Address{Net: d.net, SubUni: d.subUni})
 	return nil
 }
 
-// Function writes incremented value to channel of universe specified in command for single Artnet device
-func (d *artnetDevice) IncrementChannel(ctx context.Context, command models.IncrementChannel) error {
-	err := d.BaseDevice.IncrementChannel(ctx, &command)
-	if err != nil {
-		return err
-	}
-
-	cmd := models.SetChannel{
-		Channel:     command.Channel,
-		Value:       int(d.Universe[command.Channel]),
-		DeviceAlias: d.Alias}
+// Function sends ArtDMX packet directly to unicast Artnet node, failed write marks device as disconnected
+func (d *artnetDevice) writeFrameToNode(frame [512]byte) error {
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
 
-	err = d.WriteValueToChannel(cmd)
-	if err != nil {
-		return err
+	d.sequence++
+	if d.sequence == 0 {
+		d.sequence = 1
 	}
-	return nil
-}
 
-// Function writes universe to single Artnet device
-func (d *artnetDevice) WriteUniverseToDevice() error {
-	err := d.BaseDevice.WriteUniverseToDevice()
-	if err != nil {
-		return err
+	p := &packet.ArtDMXPacket{
+		Sequence: d.sequence,
+		SubUni:   d.subUni,
+		Net:      d.net,
+		Length:   512,
+		Data:     frame,
 	}
-
-	d.dev.SendDMXToAddress(d.Universe, artnet.Address{Net: d.net, SubUni: d.subUni})
-	return nil
-}
-
-// Function writes value to channel of universe specified in command for single Artnet device
-func (d *artnetDevice) WriteValueToChannel(command models.SetChannel) error {
-	err := d.BaseDevice.WriteValueToChannel(command)
+	data, err := p.MarshalBinary()
 	if err != nil {
-		return err
+		return fmt.Errorf("encoding ArtDMX packet error: %v", err)
 	}
 
-	if command.Channel < 0 || command.Channel >= 511 {
-		return fmt.Errorf("channel number should be beetwen 0 and 511, but got: %v", command.Channel)
+	_, err = d.conn.Write(data)
+	if err != nil {
+		d.conn.Close()
+		d.Connected.CompareAndSwap(true, false)
+		return fmt.Errorf("sending frame to device error: %v", err)
 	}
-	d.dev.SendDMXToAddress(d.Universe, artnet.Address{Net: d.net, SubUni: d.subUni})
-
 	return nil
 }
 
-// Function handles blackout for whole DMX universe of single Artnet device
-func (d *artnetDevice) Blackout(ctx context.Context) error {
-	err := d.BaseDevice.Blackout(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = d.WriteUniverseToDevice()
-	if err != nil {
-		return err
+// Function frees resources of Artnet device entity
+func (d *artnetDevice) Close() {
+	d.BaseDevice.Close()
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
+	if d.address != nil && d.Connected.Load() {
+		d.conn.Close()
 	}
-	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,111 @@
+package artnet
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"github.com/jsimonetti/go-artnet"
+	"github.com/jsimonetti/go-artnet/packet"
+	"go.uber.org/zap"
+)
+
+// Representation of Artnet input entity feeding received port-address into device
+type Input struct {
+	Net     uint8
+	SubUni  uint8
+	Mode    string
+	Timeout time.Duration
+	Device  device.Device
+	source  string // input layer of device, one per port-address
+}
+
+// Representation of Artnet receiver entity listening for ArtDMX packets
+type Receiver struct {
+	conn   *net.UDPConn
+	inputs map[artnet.Address][]Input
+	logger *zap.Logger
+	done   chan struct{}
+}
+
+// Function initializes Artnet receiver entity and starts listening for ArtDMX packets.
+// Address has no default: Artnet port 6454 is already bound by controller of Artnet devices on the same host.
+func NewReceiver(address string, inputs []Input, logger *zap.Logger) (*Receiver, error) {
+	if address == "" {
+		return nil, fmt.Errorf("ArtNet input address must be provided")
+	}
+	udpAddress, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ArtNet input address '%s': %v", address, err)
+	}
+	conn, err := net.ListenUDP("udp4", udpAddress)
+	if err != nil {
+		return nil, fmt.Errorf("listening ArtNet input address '%s' failed: %v", address, err)
+	}
+
+	receiver := &Receiver{
+		conn:   conn,
+		inputs: make(map[artnet.Address][]Input),
+		logger: logger.With(zap.String("artnet_input", address)),
+		done:   make(chan struct{}),
+	}
+	for _, input := range inputs {
+		address := artnet.Address{Net: input.Net, SubUni: input.SubUni}
+		input.source = fmt.Sprintf("artnet %d:%d", input.Net, input.SubUni)
+		receiver.inputs[address] = append(receiver.inputs[address], input)
+	}
+
+	go receiver.run()
+	return receiver, nil
+}
+
+// Function reads ArtDMX packets and merges their data into devices until receiver is closed
+func (r *Receiver) run() {
+	defer close(r.done)
+
+	buf := make([]byte, 1024)
+	for {
+		n, _, err := r.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			r.logger.Debug("reading ArtNet input failed", zap.Error(err))
+			continue
+		}
+
+		address, data, err := ParseArtDMX(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, input := range r.inputs[address] {
+			input.Device.MergeInput(input.source, input.Mode, input.Timeout, data)
+		}
+	}
+}
+
+// Function returns port-address and channel data of ArtDMX packet, other Artnet packets and foreign data are rejected
+func ParseArtDMX(data []byte) (artnet.Address, []byte, error) {
+	p, err := packet.Unmarshal(data)
+	if err != nil {
+		return artnet.Address{}, nil, err
+	}
+	dmx, ok := p.(*packet.ArtDMXPacket)
+	if !ok {
+		return artnet.Address{}, nil, fmt.Errorf("not an ArtDMX packet")
+	}
+
+	length := int(dmx.Length)
+	if length > 512 {
+		length = 512
+	}
+	return artnet.Address{Net: dmx.Net, SubUni: dmx.SubUni}, dmx.Data[:length], nil
+}
+
+// Function stops Artnet receiver
+func (r *Receiver) Close() {
+	r.conn.Close()
+	<-r.done
+}
@@ -0,0 +1,128 @@
+package artnet
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"github.com/jsimonetti/go-artnet"
+	"github.com/jsimonetti/go-artnet/packet"
+	"go.uber.org/zap"
+)
+
+// Function returns marshalled ArtDMX packet with data of specified length
+func artDMX(t *testing.T, netID uint8, subUni uint8, data []byte) []byte {
+	t.Helper()
+
+	p := &packet.ArtDMXPacket{Net: netID, SubUni: subUni}
+	copy(p.Data[:], data)
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshalling ArtDMX packet failed: %v", err)
+	}
+	// packet is always marshalled with 512 slots, length is patched for shorter frames
+	if len(data) < 512 {
+		b[16], b[17] = byte(len(data)>>8), byte(len(data))
+		b = b[:18+len(data)]
+	}
+	return b
+}
+
+func TestParseArtDMX(t *testing.T) {
+	frame := bytes.Repeat([]byte{0x10, 0x20}, 256)
+	valid := artDMX(t, 1, 0x23, frame)
+	short := artDMX(t, 0, 1, []byte{1, 2, 3, 4})
+
+	poll, err := (&packet.ArtPollPacket{}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshalling ArtPoll packet failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		address artnet.Address
+		want    []byte
+		wantErr bool
+	}{
+		{name: "full frame", data: valid, address: artnet.Address{Net: 1, SubUni: 0x23}, want: frame},
+		{name: "short frame", data: short, address: artnet.Address{Net: 0, SubUni: 1}, want: []byte{1, 2, 3, 4}},
+		{name: "truncated header", data: valid[:10], wantErr: true},
+		{name: "truncated data", data: valid[:100], wantErr: true},
+		{name: "odd length", data: append(artDMX(t, 0, 1, []byte{1, 2})[:17], 3, 1, 2, 3), wantErr: true},
+		{name: "ArtPoll", data: poll, wantErr: true},
+		{name: "sACN packet", data: append([]byte{0x00, 0x10, 0x00, 0x00}, []byte("ASC-E1.17\x00\x00\x00")...), wantErr: true},
+		{name: "empty", data: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, data, err := ParseArtDMX(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseArtDMX() accepted packet, address %v", address)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArtDMX() error = %v", err)
+			}
+			if address != tt.address {
+				t.Errorf("address = %v, want %v", address, tt.address)
+			}
+			if !bytes.Equal(data, tt.want) {
+				t.Errorf("data = %v, want %v", data, tt.want)
+			}
+		})
+	}
+}
+
+// Representation of device recording merged input frames
+type inputRecorder struct {
+	device.Device
+	frames chan []byte
+}
+
+// Function records merged input frame
+func (r *inputRecorder) MergeInput(source string, mode string, timeout time.Duration, data []byte) {
+	frame := make([]byte, len(data))
+	copy(frame, data)
+	r.frames <- frame
+}
+
+func TestReceiverRoutesArtDMXToInputs(t *testing.T) {
+	recorder := &inputRecorder{frames: make(chan []byte, 4)}
+	receiver, err := NewReceiver("127.0.0.1:0", []Input{{Net: 0, SubUni: 1, Mode: device.MergeModeHTP, Device: recorder}}, zap.NewNop())
+	if err != nil {
+		t.Skipf("loopback UDP is not available: %v", err)
+	}
+	defer receiver.Close()
+
+	conn, err := net.DialUDP("udp4", nil, receiver.conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatalf("dialing receiver failed: %v", err)
+	}
+	defer conn.Close()
+
+	conn.Write(artDMX(t, 0, 2, []byte{9, 9}))             // other port-address
+	conn.Write([]byte("not an Artnet packet"))            // foreign data
+	conn.Write(artDMX(t, 0, 1, []byte{1, 2, 3, 4, 5, 6})) // routed
+
+	select {
+	case frame := <-recorder.frames:
+		if !bytes.Equal(frame, []byte{1, 2, 3, 4, 5, 6}) {
+			t.Errorf("merged frame = %v", frame)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no frame was merged into device")
+	}
+}
+
+func TestNewReceiverRequiresAddress(t *testing.T) {
+	_, err := NewReceiver("", nil, zap.NewNop())
+	if err == nil {
+		t.Fatalf("NewReceiver() accepted empty address")
+	}
+}
@@ -0,0 +1,207 @@
+package cue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+)
+
+// Representation of function applying scene of cue to device
+type SceneApplier func(ctx context.Context, command models.SetScene) error
+
+// Representation of cue entity
+type Cue struct {
+	DeviceAlias string
+	SceneAlias  string
+	FadeIn      time.Duration
+	FadeOut     time.Duration
+	Delay       time.Duration
+	AutoFollow  time.Duration
+}
+
+// Representation of cue list player entity
+type Player struct {
+	Alias      string
+	Cues       []Cue
+	Current    int
+	apply      SceneApplier
+	signals    chan core.Signal
+	logger     *zap.Logger
+	timer      *time.Timer
+	generation int
+	mutex      sync.Mutex
+}
+
+// Function initializes cue list player entity from user configuration
+func NewPlayer(conf device.CueListConfig, apply SceneApplier, signals chan core.Signal, logger *zap.Logger) *Player {
+	cues := make([]Cue, 0, len(conf.Cues))
+	for _, cueConfig := range conf.Cues {
+		fadeOut := cueConfig.FadeOut
+		if fadeOut == 0 {
+			fadeOut = cueConfig.FadeIn
+		}
+		cues = append(cues, Cue{
+			DeviceAlias: cueConfig.DeviceAlias,
+			SceneAlias:  cueConfig.SceneAlias,
+			FadeIn:      time.Duration(cueConfig.FadeIn) * time.Millisecond,
+			FadeOut:     time.Duration(fadeOut) * time.Millisecond,
+			Delay:       time.Duration(cueConfig.Delay) * time.Millisecond,
+			AutoFollow:  time.Duration(cueConfig.AutoFollow) * time.Millisecond,
+		})
+	}
+
+	return &Player{
+		Alias:   conf.Alias,
+		Cues:    cues,
+		Current: -1,
+		apply:   apply,
+		signals: signals,
+		logger:  logger.With(zap.String("cue_list", conf.Alias)),
+	}
+}
+
+// Function starts next cue of cue list
+func (p *Player) Go(ctx context.Context) error {
+	return p.startCue(ctx, func() (int, error) {
+		if p.Current+1 >= len(p.Cues) {
+			return 0, fmt.Errorf("end of cue list '%s' is reached", p.Alias)
+		}
+		return p.Current + 1, nil
+	})
+}
+
+// Function starts previous cue of cue list
+func (p *Player) Back(ctx context.Context) error {
+	return p.startCue(ctx, func() (int, error) {
+		if p.Current <= 0 {
+			return 0, fmt.Errorf("beginning of cue list '%s' is reached", p.Alias)
+		}
+		return p.Current - 1, nil
+	})
+}
+
+// Function starts cue of cue list by index
+func (p *Player) Goto(ctx context.Context, index int) error {
+	return p.startCue(ctx, func() (int, error) {
+		if index < 0 || index >= len(p.Cues) {
+			return 0, fmt.Errorf("cue index '%d' out of range [0, %d] of cue list '%s'", index, len(p.Cues)-1, p.Alias)
+		}
+		return index, nil
+	})
+}
+
+// Function starts cue selected by index function under player mutex, cue changed signal is sent after unlocking
+func (p *Player) startCue(ctx context.Context, selectCue func() (int, error)) error {
+	p.mutex.Lock()
+	index, err := selectCue()
+	var signal models.CueChanged
+	if err == nil {
+		signal, err = p.start(ctx, index)
+	}
+	p.mutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+	p.signals <- signal
+	return nil
+}
+
+// Function stops pending delayed and auto-follow cues
+func (p *Player) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.generation++
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+}
+
+// Function schedules scene change of cue and makes it current if it is applied or delayed, caller must hold player mutex.
+// Returns cue changed signal to be sent by caller after unlocking.
+func (p *Player) start(ctx context.Context, index int) (models.CueChanged, error) {
+	p.generation++
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+
+	cue := p.Cues[index]
+	generation := p.generation
+
+	if cue.Delay > 0 {
+		p.timer = time.AfterFunc(cue.Delay, func() {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+
+			if generation != p.generation {
+				return
+			}
+			err := p.fire(ctx, index)
+			if err != nil {
+				p.logger.Warn("delayed cue failed", zap.Int("cue", index), zap.Error(err))
+			}
+		})
+	} else {
+		err := p.fire(ctx, index)
+		if err != nil {
+			return models.CueChanged{}, err
+		}
+	}
+	p.Current = index
+
+	return models.CueChanged{
+		CueListAlias: p.Alias,
+		CueIndex:     index,
+		DeviceAlias:  cue.DeviceAlias,
+		SceneAlias:   cue.SceneAlias,
+	}, nil
+}
+
+// Function applies scene of cue and schedules auto-follow cue, caller must hold player mutex
+func (p *Player) fire(ctx context.Context, index int) error {
+	cue := p.Cues[index]
+	err := p.apply(ctx, models.SetScene{
+		DeviceAlias: cue.DeviceAlias,
+		SceneAlias:  cue.SceneAlias,
+		FadeMs:      int(cue.FadeIn / time.Millisecond),
+		FadeOutMs:   int(cue.FadeOut / time.Millisecond),
+	})
+	if err != nil {
+		return fmt.Errorf("cue '%d' of cue list '%s' failed: %v", index, p.Alias, err)
+	}
+
+	if cue.AutoFollow == 0 || index+1 >= len(p.Cues) {
+		return nil
+	}
+
+	fade := cue.FadeIn
+	if cue.FadeOut > fade {
+		fade = cue.FadeOut
+	}
+	generation := p.generation
+	p.timer = time.AfterFunc(fade+cue.AutoFollow, func() {
+		p.mutex.Lock()
+		if generation != p.generation {
+			p.mutex.Unlock()
+			return
+		}
+		signal, err := p.start(ctx, index+1)
+		p.mutex.Unlock()
+
+		if err != nil {
+			p.logger.Warn("auto-follow cue failed", zap.Int("cue", index+1), zap.Error(err))
+			return
+		}
+		p.signals <- signal
+	})
+	return nil
+}
@@ -0,0 +1,46 @@
+package cue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+)
+
+func TestSlowSignalConsumerDoesNotBlockPlayer(t *testing.T) {
+	conf := device.CueListConfig{Alias: "list", Cues: []device.CueConfig{
+		{DeviceAlias: "dev", SceneAlias: "first"},
+		{DeviceAlias: "dev", SceneAlias: "second"},
+	}}
+	signals := make(chan core.Signal)
+	player := NewPlayer(conf, func(context.Context, models.SetScene) error { return nil }, signals, zap.NewNop())
+
+	goDone := make(chan error)
+	go func() {
+		goDone <- player.Go(context.Background())
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		player.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("Close is blocked by pending cue changed signal")
+	}
+
+	signal := (<-signals).(models.CueChanged)
+	if signal.CueIndex != 0 || signal.SceneAlias != "first" {
+		t.Errorf("signal = %+v, want first cue", signal)
+	}
+	if err := <-goDone; err != nil {
+		t.Errorf("Go() error = %v", err)
+	}
+}
@@ -2,11 +2,14 @@ package device
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"git.miem.hse.ru/hubman/dmx-executor/internal/effects"
 	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
 	"git.miem.hse.ru/hubman/hubman-lib/core"
 	"go.uber.org/zap"
@@ -20,8 +23,15 @@ const (
 type BaseDevice struct {
 	Alias               string
 	Universe            [512]byte
+	Output              [512]byte // last frame built by output loop
 	NonBlackoutChannels map[int]struct{}
+	ProtectedChannels   map[int]struct{} // channels never written by SetUniverse
+	AllowSetUniverse    bool
 	Scenes              map[string]Scene
+	CreatedScenes       map[string]struct{} // scenes created at runtime
+	RemovedScenes       map[string]struct{} // configured scenes removed or renamed at runtime
+	ConfiguredScenes    map[string]struct{} // scenes of user configuration
+	Patch               map[string]Channel
 	CurrentScene        *Scene
 	Signals             chan core.Signal
 	Logger              *zap.Logger
@@ -30,19 +40,38 @@ type BaseDevice struct {
 	StopReconnect       chan struct{}
 	Mutex               sync.Mutex
 	CheckManager        core.CheckRegistry
+	Store               Store
+	Persister           *Persister
+	Fader               *FadeEngine
+	SceneFade           *FadeGroup
+	Effects             *effects.Engine
+	Inputs              InputLayers
+	FrameInterval       time.Duration
+	KeepAliveInterval   time.Duration
+	StopOutput          chan struct{}
 }
 
 // Function initiliazes base device entity
-func NewBaseDevice(ctx context.Context, alias string, nonBlackoutChannels []int, scenes []SceneConfig, reconnectInterval int, signals chan core.Signal, logger *zap.Logger, checkManager core.CheckRegistry) *BaseDevice {
+func NewBaseDevice(ctx context.Context, alias string, nonBlackoutChannels []int, scenes []SceneConfig, patch map[string]Channel, reconnectInterval int, frameRate int, signals chan core.Signal, logger *zap.Logger, checkManager core.CheckRegistry, persister *Persister) *BaseDevice {
 	if reconnectInterval < DefaultReconnectInterval {
 		reconnectInterval = DefaultReconnectInterval
 	}
-	
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+
 	device := BaseDevice{
 		Alias:               alias,
 		Universe:            [512]byte{},
+		Output:              [512]byte{},
 		NonBlackoutChannels: make(map[int]struct{}),
+		ProtectedChannels:   make(map[int]struct{}),
+		AllowSetUniverse:    false,
 		Scenes:              make(map[string]Scene),
+		CreatedScenes:       make(map[string]struct{}),
+		RemovedScenes:       make(map[string]struct{}),
+		ConfiguredScenes:    make(map[string]struct{}),
+		Patch:               patch,
 		CurrentScene:        nil,
 		Signals:             signals,
 		Logger:              logger.With(zap.String("device", alias)),
@@ -51,12 +80,25 @@ func NewBaseDevice(ctx context.Context, alias string, nonBlackoutChannels []int,
 		StopReconnect:       make(chan struct{}),
 		Mutex:               sync.Mutex{},
 		CheckManager:        checkManager,
+		Store:               persister.Store,
+		Persister:           persister,
+		Fader:               NewFadeEngine(),
+		SceneFade:           nil,
+		Effects:             effects.NewEngine(),
+		Inputs:              make(InputLayers),
+		FrameInterval:       time.Second / time.Duration(frameRate),
+		KeepAliveInterval:   0,
+		StopOutput:          make(chan struct{}),
 	}
 
 	device.NonBlackoutChannels = ReadNonBlackoutChannelsFromDeviceConfig(nonBlackoutChannels)
-	device.Scenes = ReadScenesFromDeviceConfig(scenes)
+	device.Scenes = ReadScenesFromDeviceConfig(scenes, patch)
+	for sceneAlias := range device.Scenes {
+		device.ConfiguredScenes[sceneAlias] = struct{}{}
+	}
 	device.GetUniverseFromCache(ctx)
 	device.GetScenesFromCache(ctx)
+	device.GetCurrentSceneFromCache(ctx)
 	return &device
 }
 
@@ -68,12 +110,9 @@ func (b *BaseDevice) GetUniverseFromCache(ctx context.Context) {
 	}
 }
 
-// Function saves universe of single device to cache
-func (b *BaseDevice) SaveUniverseToCache(ctx context.Context) {
-	err := b.WriteUniverse(ctx)
-	if err != nil {
-		b.Logger.Warn("save universe to cache failed", zap.Error(err))
-	}
+// Function schedules saving universe of single device to cache, universe is written by persister
+func (b *BaseDevice) SaveUniverseToCache(_ context.Context) {
+	b.Persister.MarkDirty(b)
 }
 
 // Function gets scene of single device from cache
@@ -81,6 +120,14 @@ func (b *BaseDevice) GetScenesFromCache(ctx context.Context) {
 	b.ReadScenes(ctx)
 }
 
+// Function restores current scene of single device from cache, universe is not changed
+func (b *BaseDevice) GetCurrentSceneFromCache(ctx context.Context) {
+	err := b.ReadCurrentScene(ctx)
+	if err != nil {
+		b.Logger.Warn("get current scene from cache failed", zap.Error(err))
+	}
+}
+
 // Function save scene of single device to cache
 func (b *BaseDevice) SaveScenesToCache(ctx context.Context) {
 	b.WriteScenes(ctx)
@@ -91,95 +138,574 @@ func (b *BaseDevice) GetAlias() string {
 	return b.Alias
 }
 
+// Function returns base device entity of single device
+func (b *BaseDevice) GetBaseDevice() *BaseDevice {
+	return b
+}
+
 // Function sets scene of single device
 func (b *BaseDevice) SetScene(ctx context.Context, command models.SetScene) error {
 	if !b.Connected.Load() {
 		return fmt.Errorf("no connection to device")
 	}
 
+	_, err := ParseFade(command.FadeMs, "")
+	if err != nil {
+		return err
+	}
+	_, err = ParseFade(command.FadeOutMs, "")
+	if err != nil {
+		return err
+	}
+	if command.FadeOutMs == 0 {
+		command.FadeOutMs = command.FadeMs
+	}
+
+	b.Mutex.Lock()
 	scene, ok := b.Scenes[command.SceneAlias]
 	if !ok {
+		b.Mutex.Unlock()
 		return fmt.Errorf("invalid scene alias '%s'", command.SceneAlias)
 	}
-	b.CurrentScene = &scene
+	b.SelectScene(&scene)
+	completed := b.ApplyScene(scene, command.FadeMs, command.FadeOutMs, time.Now(), func() {
+		b.SaveUniverseToCache(ctx)
+		b.CreateSceneChangedSignal(scene.Alias)
+	})
+	b.Mutex.Unlock()
+
+	b.SaveUniverseToCache(ctx)
+	for _, onComplete := range completed {
+		onComplete()
+	}
 	return nil
 }
 
+// Function makes scene current scene of single device, effects on channels of scene keep running,
+// other effects are stopped. Caller must hold device mutex.
+func (b *BaseDevice) SelectScene(scene *Scene) {
+	b.CurrentScene = scene
+	channels := make(map[int]effects.Channel, len(scene.ChannelMap))
+	for _, channel := range scene.ChannelMap {
+		channels[channel.UniverseChannelID] = channel
+	}
+	b.Effects.Retain(channels)
+}
+
+// Function writes scene values to universe or starts crossfade to them, caller must hold device mutex.
+// Returns callbacks to be called by caller after unlocking: callbacks of fades superseded by scene and
+// callback of scene if it is applied immediately, otherwise callback is called when crossfade is completed.
+func (b *BaseDevice) ApplyScene(scene Scene, fadeMs int, fadeOutMs int, startedAt time.Time, onComplete func()) []func() {
+	completed := b.Fader.CancelGroup(b.SceneFade)
+	b.SceneFade = nil
+
+	if (fadeMs == 0 && fadeOutMs == 0) || len(scene.ChannelMap) == 0 {
+		for _, channel := range scene.ChannelMap {
+			completed = append(completed, b.Fader.CancelChannel(channel.UniverseChannelID)...)
+			channel.Write(&b.Universe, channel.Value)
+		}
+		return append(completed, onComplete)
+	}
+
+	group := &FadeGroup{
+		Pending:    make(map[int]struct{}),
+		OnComplete: onComplete,
+	}
+	for _, channel := range scene.ChannelMap {
+		from := channel.Read(&b.Universe)
+		duration := fadeMs
+		if channel.Value < from {
+			duration = fadeOutMs
+		}
+		completed = append(completed, b.Fader.AddFade(&ChannelFade{
+			Channel:   channel,
+			From:      from,
+			To:        channel.Value,
+			StartedAt: startedAt,
+			Duration:  time.Duration(duration) * time.Millisecond,
+			Group:     group,
+		})...)
+	}
+	b.SceneFade = group
+	return completed
+}
+
+// Function runs output loop sending universe to device with configured frame rate until device is closed.
+// Unchanged frames are resent only after keep-alive interval if it is set.
+func (b *BaseDevice) RunOutput(render func(frame [512]byte) error) {
+	ticker := time.NewTicker(b.FrameInterval)
+	var lastFrame [512]byte
+	var lastSentAt time.Time
+	sent := false
+
+	for {
+		select {
+		case <-b.StopOutput:
+			ticker.Stop()
+			return
+		case now := <-ticker.C:
+			b.Mutex.Lock()
+			completed := b.Fader.Step(now, &b.Universe)
+			frame := b.Universe
+			b.Effects.Apply(now, &frame)
+			b.Inputs.Apply(now, &frame)
+			b.Output = frame
+			b.Mutex.Unlock()
+
+			for _, onComplete := range completed {
+				onComplete()
+			}
+
+			if !b.Connected.Load() {
+				sent = false
+				continue
+			}
+
+			if sent && b.KeepAliveInterval > 0 && frame == lastFrame && now.Sub(lastSentAt) < b.KeepAliveInterval {
+				continue
+			}
+
+			err := render(frame)
+			if err != nil {
+				sent = false
+				b.Logger.Debug("writing frame to device failed", zap.Error(err))
+				continue
+			}
+			lastFrame = frame
+			lastSentAt = now
+			sent = true
+		}
+	}
+}
+
+// Function merges frame received from external source into single device, every source has its own input layer.
+// LTP input overwrites universe channels changed by the source, HTP input is merged into output frame.
+func (b *BaseDevice) MergeInput(source string, mode string, timeout time.Duration, data []byte) {
+	now := time.Now()
+	var completed []func()
+
+	b.Mutex.Lock()
+	input := b.Inputs.Get(source, mode, timeout)
+	if mode == MergeModeLTP {
+		for i := 0; i < len(data) && i < 512; i++ {
+			if input.Received && input.Frame[i] == data[i] {
+				continue
+			}
+			completed = append(completed, b.Fader.CancelChannel(i)...)
+			b.Universe[i] = data[i]
+		}
+	}
+	input.Update(now, data)
+	b.Mutex.Unlock()
+
+	for _, onComplete := range completed {
+		onComplete()
+	}
+}
+
 // Function saves scene of single device
 func (b *BaseDevice) SaveScene(ctx context.Context) error {
+	b.Mutex.Lock()
 	if b.CurrentScene == nil {
+		b.Mutex.Unlock()
 		return fmt.Errorf("no scene is selected")
 	}
 
 	for sceneChannelID, channel := range b.CurrentScene.ChannelMap {
-		channel.Value = int(b.Universe[channel.UniverseChannelID])
+		channel.Value = channel.Read(&b.Universe)
 		b.CurrentScene.ChannelMap[sceneChannelID] = channel
 	}
+	sceneAlias := b.CurrentScene.Alias
+	b.Mutex.Unlock()
 
 	b.SaveScenesToCache(ctx)
-	b.CreateSceneSavedSignal()
+	b.CreateSceneSavedSignal(sceneAlias)
 	return nil
 }
 
-// Function sets channel of single device
-func (b *BaseDevice) SetChannel(ctx context.Context, command *models.SetChannel) error {
+// Function captures values of current scene channels from received input frame of single device
+func (b *BaseDevice) LearnScene(ctx context.Context) error {
+	b.Mutex.Lock()
+	if b.CurrentScene == nil {
+		b.Mutex.Unlock()
+		return fmt.Errorf("no scene is selected")
+	}
+	input := b.Inputs.Latest(time.Now())
+	if input == nil {
+		b.Mutex.Unlock()
+		return fmt.Errorf("no input frame is received")
+	}
+
+	for sceneChannelID, channel := range b.CurrentScene.ChannelMap {
+		channel.Value = channel.Read(&input.Frame)
+		b.CurrentScene.ChannelMap[sceneChannelID] = channel
+	}
+	sceneAlias := b.CurrentScene.Alias
+	b.Mutex.Unlock()
+
+	b.SaveScenesToCache(ctx)
+	b.CreateSceneSavedSignal(sceneAlias)
+	return nil
+}
+
+// Function sets channel of single device, value of 16-bit channel is scaled to full range
+func (b *BaseDevice) SetChannel(ctx context.Context, command models.SetChannel) error {
+	if command.Value < 0 || command.Value > 255 {
+		return fmt.Errorf("channel value '%d' out of range [0, 255]", command.Value)
+	}
+
+	return b.setChannel(ctx, command.Channel, command.Attribute, command.Value, 8, command.FadeMs, command.Easing)
+}
+
+// Function sets several channels of current scene of single device in one frame, all values are validated before any is applied.
+// Value of 16-bit channel is scaled to full range.
+func (b *BaseDevice) SetChannels(ctx context.Context, command models.SetChannels) error {
 	if !b.Connected.Load() {
 		return fmt.Errorf("no connection to device")
 	}
 
+	easing, err := ParseFade(command.FadeMs, command.Easing)
+	if err != nil {
+		return err
+	}
+
+	values := make([]models.ChannelValue, 0, len(command.Channels)+len(command.Values))
+	values = append(values, command.Channels...)
+	for idx, value := range command.Values {
+		values = append(values, models.ChannelValue{Channel: command.StartChannel + idx, Value: value})
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("no channels specified")
+	}
+
+	for _, channelValue := range values {
+		if channelValue.Value < 0 || channelValue.Value > 255 {
+			return fmt.Errorf("channel value '%d' out of range [0, 255]", channelValue.Value)
+		}
+	}
+
+	var group *FadeGroup
+	if command.FadeMs > 0 {
+		group = &FadeGroup{
+			Pending: make(map[int]struct{}),
+			OnComplete: func() {
+				b.SaveUniverseToCache(ctx)
+			},
+		}
+	}
+
+	b.Mutex.Lock()
 	if b.CurrentScene == nil {
+		b.Mutex.Unlock()
 		return fmt.Errorf("no scene is selected")
 	}
+	channels := make([]Channel, 0, len(values))
+	universeChannelIDs := make(map[int]struct{})
+	for _, channelValue := range values {
+		channel, err := b.ResolveSceneChannel(channelValue.Channel, channelValue.Attribute)
+		if err != nil {
+			b.Mutex.Unlock()
+			return err
+		}
+		if _, ok := universeChannelIDs[channel.UniverseChannelID]; ok {
+			b.Mutex.Unlock()
+			return fmt.Errorf("universe channel '%d' is set more than once", channel.UniverseChannelID)
+		}
+		universeChannelIDs[channel.UniverseChannelID] = struct{}{}
+		channel.Value = ScaleValue(channelValue.Value, 8, channel)
+		channels = append(channels, channel)
+	}
 
-	channel, ok := b.CurrentScene.ChannelMap[command.Channel]
-	if !ok {
-		return fmt.Errorf("channel '%d' doesn't belong to current scene '%s'", command.Channel, b.CurrentScene.Alias)
+	startedAt := time.Now()
+	var completed []func()
+	for _, channel := range channels {
+		if group == nil {
+			completed = append(completed, b.Fader.CancelChannel(channel.UniverseChannelID)...)
+			channel.Write(&b.Universe, channel.Value)
+			continue
+		}
+		completed = append(completed, b.Fader.AddFade(&ChannelFade{
+			Channel:   channel,
+			From:      channel.Read(&b.Universe),
+			To:        channel.Value,
+			StartedAt: startedAt,
+			Duration:  time.Duration(command.FadeMs) * time.Millisecond,
+			Easing:    easing,
+			Group:     group,
+		})...)
+	}
+	b.Mutex.Unlock()
+
+	for _, onComplete := range completed {
+		onComplete()
+	}
+	if group == nil {
+		b.SaveUniverseToCache(ctx)
 	}
-	command.Channel = channel.UniverseChannelID
-	b.Universe[command.Channel] = byte(command.Value)
-	b.SaveUniverseToCache(ctx)
 	return nil
 }
 
-// Function increments channel of single device
-func (b *BaseDevice) IncrementChannel(ctx context.Context, command *models.IncrementChannel) error {
+// Function sets 16-bit channel of single device, value of 8-bit channel is reduced to its coarse part
+func (b *BaseDevice) SetChannel16(ctx context.Context, command models.SetChannel16) error {
+	if command.Value < 0 || command.Value > 65535 {
+		return fmt.Errorf("channel value '%d' out of range [0, 65535]", command.Value)
+	}
+
+	return b.setChannel(ctx, command.Channel, command.Attribute, command.Value, 16, command.FadeMs, command.Easing)
+}
+
+// Function increments channel of single device, increment of 16-bit channel is scaled to full range
+func (b *BaseDevice) IncrementChannel(ctx context.Context, command models.IncrementChannel) error {
+	return b.incrementChannel(ctx, command.Channel, command.Attribute, command.Value, 8, command.FadeMs, command.Easing)
+}
+
+// Function increments 16-bit channel of single device, increment of 8-bit channel is reduced to its coarse part
+func (b *BaseDevice) IncrementChannel16(ctx context.Context, command models.IncrementChannel16) error {
+	return b.incrementChannel(ctx, command.Channel, command.Attribute, command.Value, 16, command.FadeMs, command.Easing)
+}
+
+// Function sets channel of current scene to value of specified resolution (8 or 16 bits)
+func (b *BaseDevice) setChannel(ctx context.Context, sceneChannelID int, fixtureAttribute string, value int, bits int, fadeMs int, easingName string) error {
 	if !b.Connected.Load() {
 		return fmt.Errorf("no connection to device")
 	}
 
+	easing, err := ParseFade(fadeMs, easingName)
+	if err != nil {
+		return err
+	}
+
+	b.Mutex.Lock()
 	if b.CurrentScene == nil {
+		b.Mutex.Unlock()
 		return fmt.Errorf("no scene is selected")
 	}
+	channel, err := b.ResolveSceneChannel(sceneChannelID, fixtureAttribute)
+	if err != nil {
+		b.Mutex.Unlock()
+		return err
+	}
+	completed := b.UpdateChannel(ctx, channel, ScaleValue(value, bits, channel), fadeMs, easing)
+	b.Mutex.Unlock()
 
-	channel, ok := b.CurrentScene.ChannelMap[command.Channel]
-	if !ok {
-		return fmt.Errorf("channel '%d' doesn't belong to current scene '%s'", command.Channel, b.CurrentScene.Alias)
+	for _, onComplete := range completed {
+		onComplete()
 	}
+	if fadeMs == 0 {
+		b.SaveUniverseToCache(ctx)
+	}
+	return nil
+}
 
-	command.Channel = channel.UniverseChannelID
-	command.Value = int(b.Universe[command.Channel]) + command.Value
-	if (int(b.Universe[command.Channel])+command.Value) < 0 || (int(b.Universe[command.Channel])+command.Value) > 255 {
-		return fmt.Errorf("incremented channel value '%d' out of range [0, 255]", command.Value)
+// Function increments channel of current scene by value of specified resolution (8 or 16 bits)
+func (b *BaseDevice) incrementChannel(ctx context.Context, sceneChannelID int, fixtureAttribute string, increment int, bits int, fadeMs int, easingName string) error {
+	if !b.Connected.Load() {
+		return fmt.Errorf("no connection to device")
 	}
 
-	b.Universe[command.Channel] = byte(command.Value)
-	b.SaveUniverseToCache(ctx)
+	easing, err := ParseFade(fadeMs, easingName)
+	if err != nil {
+		return err
+	}
+
+	b.Mutex.Lock()
+	if b.CurrentScene == nil {
+		b.Mutex.Unlock()
+		return fmt.Errorf("no scene is selected")
+	}
+	channel, err := b.ResolveSceneChannel(sceneChannelID, fixtureAttribute)
+	if err != nil {
+		b.Mutex.Unlock()
+		return err
+	}
+	value := channel.Read(&b.Universe)
+	target, fading := b.Fader.Target(channel.UniverseChannelID)
+	if fading {
+		value = target
+	}
+	value += ScaleValue(increment, bits, channel)
+	if value < 0 || value > channel.MaxValue() {
+		b.Mutex.Unlock()
+		return fmt.Errorf("incremented channel value '%d' out of range [0, %d]", value, channel.MaxValue())
+	}
+	completed := b.UpdateChannel(ctx, channel, value, fadeMs, easing)
+	b.Mutex.Unlock()
+
+	for _, onComplete := range completed {
+		onComplete()
+	}
+	if fadeMs == 0 {
+		b.SaveUniverseToCache(ctx)
+	}
 	return nil
 }
 
-// Function writes value to channel of single device
-func (b *BaseDevice) WriteValueToChannel(command models.SetChannel) error {
+// Function converts value of specified resolution (8 or 16 bits) to resolution of channel
+func ScaleValue(value int, bits int, channel Channel) int {
+	switch {
+	case bits == 8 && channel.Wide:
+		return value * 257
+	case bits == 16 && !channel.Wide:
+		return value / 257
+	default:
+		return value
+	}
+}
+
+// Function writes value to universe channel immediately or starts its fade, caller must hold device mutex
+func (b *BaseDevice) UpdateChannel(ctx context.Context, channel Channel, value int, fadeMs int, easing EasingFunc) []func() {
+	if fadeMs == 0 {
+		completed := b.Fader.CancelChannel(channel.UniverseChannelID)
+		channel.Write(&b.Universe, value)
+		return completed
+	}
+
+	return b.Fader.AddFade(&ChannelFade{
+		Channel:   channel,
+		From:      channel.Read(&b.Universe),
+		To:        value,
+		StartedAt: time.Now(),
+		Duration:  time.Duration(fadeMs) * time.Millisecond,
+		Easing:    easing,
+		Group: &FadeGroup{
+			Pending: make(map[int]struct{}),
+			OnComplete: func() {
+				b.SaveUniverseToCache(ctx)
+			},
+		},
+	})
+}
+
+// Function starts effect on channels of current scene of single device
+func (b *BaseDevice) StartEffect(ctx context.Context, command models.StartEffect) error {
 	if !b.Connected.Load() {
 		return fmt.Errorf("no connection to device")
 	}
+
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+
+	channels, err := b.ResolveSceneChannels(command.Channels)
+	if err != nil {
+		return err
+	}
+	if len(channels) == 0 {
+		return fmt.Errorf("no channels specified for effect")
+	}
+
+	startedAt := time.Now()
+	channelEffects := make(map[int]*effects.Effect)
+	for _, channel := range channels {
+		effect, err := effects.NewEffect(channel, command.Waveform, command.Amplitude, command.Offset, command.PeriodMs, command.Phase, startedAt)
+		if err != nil {
+			return err
+		}
+		channelEffects[channel.UniverseChannelID] = effect
+	}
+
+	for universeChannelID, effect := range channelEffects {
+		b.Effects.Start(universeChannelID, effect)
+	}
 	return nil
 }
 
-// Function writes universe to single device
-func (b *BaseDevice) WriteUniverseToDevice() error {
+// Function stops effects on channels of current scene of single device, stops all effects if no channels specified
+func (b *BaseDevice) StopEffect(ctx context.Context, command models.StopEffect) error {
+	if len(command.Channels) == 0 {
+		b.Mutex.Lock()
+		b.Effects.StopAll()
+		b.Mutex.Unlock()
+		return nil
+	}
+
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+
+	channels, err := b.ResolveSceneChannels(command.Channels)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		b.Effects.Stop(channel.UniverseChannelID)
+	}
+	return nil
+}
+
+// Function returns channel of current scene by scene channel ID or by patched fixture attribute ("fixture.attribute") if specified,
+// caller must hold device mutex and check that scene is selected
+func (b *BaseDevice) ResolveSceneChannel(sceneChannelID int, fixtureAttribute string) (Channel, error) {
+	if fixtureAttribute == "" {
+		channel, ok := b.CurrentScene.ChannelMap[sceneChannelID]
+		if !ok {
+			return Channel{}, fmt.Errorf("channel '%d' doesn't belong to current scene '%s'", sceneChannelID, b.CurrentScene.Alias)
+		}
+		return channel, nil
+	}
+
+	patchedChannel, ok := b.Patch[fixtureAttribute]
+	if !ok {
+		return Channel{}, fmt.Errorf("fixture attribute '%s' is not patched", fixtureAttribute)
+	}
+	for _, channel := range b.CurrentScene.ChannelMap {
+		if channel.UniverseChannelID == patchedChannel.UniverseChannelID {
+			return channel, nil
+		}
+	}
+	return Channel{}, fmt.Errorf("fixture attribute '%s' doesn't belong to current scene '%s'", fixtureAttribute, b.CurrentScene.Alias)
+}
+
+// Function maps scene channel IDs of current scene to its channels, caller must hold device mutex
+func (b *BaseDevice) ResolveSceneChannels(sceneChannelIDs []int) ([]Channel, error) {
+	if b.CurrentScene == nil {
+		return nil, fmt.Errorf("no scene is selected")
+	}
+
+	channels := make([]Channel, 0, len(sceneChannelIDs))
+	for _, sceneChannelID := range sceneChannelIDs {
+		channel, ok := b.CurrentScene.ChannelMap[sceneChannelID]
+		if !ok {
+			return nil, fmt.Errorf("channel '%d' doesn't belong to current scene '%s'", sceneChannelID, b.CurrentScene.Alias)
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+// Function writes universe channels of single device from encoded frame in one frame bypassing scene channel map,
+// protected channels and channels in gaps between runs of payload are skipped
+func (b *BaseDevice) SetUniverse(ctx context.Context, command models.SetUniverse) error {
+	if !b.AllowSetUniverse {
+		return fmt.Errorf("setting universe is not allowed for device")
+	}
 	if !b.Connected.Load() {
 		return fmt.Errorf("no connection to device")
 	}
+
+	values, covered, err := DecodeUniversePayload(command.Data, command.Encoding, command.Start)
+	if err != nil {
+		return err
+	}
+
+	var completed []func()
+	b.Mutex.Lock()
+	for idx, value := range values {
+		universeChannelID := command.Start + idx
+		if !covered[idx] {
+			continue
+		}
+		if _, ok := b.ProtectedChannels[universeChannelID]; ok {
+			continue
+		}
+		completed = append(completed, b.Fader.CancelChannel(universeChannelID)...)
+		b.Universe[universeChannelID] = value
+	}
+	b.Mutex.Unlock()
+
+	for _, onComplete := range completed {
+		onComplete()
+	}
+	b.SaveUniverseToCache(ctx)
 	return nil
 }
 
@@ -189,34 +715,126 @@ func (b *BaseDevice) Blackout(ctx context.Context) error {
 		return fmt.Errorf("no connection to device")
 	}
 
+	b.Mutex.Lock()
+	completed := b.Fader.CancelGroup(b.SceneFade)
+	b.SceneFade = nil
 	for i := 0; i < 512; i++ {
 		_, ok := b.NonBlackoutChannels[i]
 		if !ok {
+			completed = append(completed, b.Fader.CancelChannel(i)...)
+			b.Effects.Stop(i)
 			b.Universe[i] = 0
 		}
 	}
+	b.Mutex.Unlock()
+
+	for _, onComplete := range completed {
+		onComplete()
+	}
 	b.SaveUniverseToCache(ctx)
 	return nil
 }
 
+// Function sends frame of single device as universe snapshot signal
+func (b *BaseDevice) GetUniverse(_ context.Context) error {
+	b.CreateUniverseSnapshotSignal()
+	return nil
+}
+
+// Function sends current scene of single device as current scene signal
+func (b *BaseDevice) GetCurrentScene(_ context.Context) error {
+	b.Mutex.Lock()
+	sceneAlias := ""
+	if b.CurrentScene != nil {
+		sceneAlias = b.CurrentScene.Alias
+	}
+	b.Mutex.Unlock()
+
+	b.SendSignal(models.CurrentScene{
+		DeviceAlias: b.Alias,
+		SceneAlias:  sceneAlias})
+	return nil
+}
+
+// Function sends scenes of single device as scene list signal
+func (b *BaseDevice) ListScenes(_ context.Context) error {
+	b.Mutex.Lock()
+	signal := models.SceneList{
+		DeviceAlias: b.Alias,
+		Scenes:      make([]string, 0, len(b.Scenes))}
+	for sceneAlias := range b.Scenes {
+		signal.Scenes = append(signal.Scenes, sceneAlias)
+	}
+	if b.CurrentScene != nil {
+		signal.CurrentScene = b.CurrentScene.Alias
+	}
+	b.Mutex.Unlock()
+
+	sort.Strings(signal.Scenes)
+	b.SendSignal(signal)
+	return nil
+}
+
+// Function creates universe snapshot signal with last frame built by output loop, snapshot is dropped if signal channel is full
+func (b *BaseDevice) CreateUniverseSnapshotSignal() {
+	b.SendSignal(b.UniverseSnapshotSignal())
+}
+
+// Function returns universe snapshot signal with last frame built by output loop
+func (b *BaseDevice) UniverseSnapshotSignal() models.UniverseSnapshot {
+	b.Mutex.Lock()
+	signal := models.UniverseSnapshot{
+		DeviceAlias: b.Alias,
+		Universe:    base64.StdEncoding.EncodeToString(b.Output[:]),
+		Connected:   b.Connected.Load()}
+	if b.CurrentScene != nil {
+		signal.SceneAlias = b.CurrentScene.Alias
+	}
+	b.Mutex.Unlock()
+
+	return signal
+}
+
+// Function sends signal of single device without blocking caller. If signal channel is full, universe snapshot
+// is dropped since next snapshot replaces it, other signals are sent from separate goroutine.
+func (b *BaseDevice) SendSignal(signal core.Signal) {
+	select {
+	case b.Signals <- signal:
+		return
+	default:
+	}
+
+	switch signal.(type) {
+	case models.UniverseSnapshot:
+		b.Logger.Debug("universe snapshot is dropped, signal channel is full")
+	default:
+		go func() {
+			b.Signals <- signal
+		}()
+	}
+}
+
 // Function creates scene changed signal
-func (b *BaseDevice) CreateSceneChangedSignal() {
+func (b *BaseDevice) CreateSceneChangedSignal(sceneAlias string) {
 	signal := models.SceneChanged{
 		DeviceAlias: b.Alias,
-		SceneAlias:  b.CurrentScene.Alias}
-	b.Signals <- signal
+		SceneAlias:  sceneAlias}
+	b.SendSignal(signal)
 }
 
 // Function creates scene saved signal
-func (b *BaseDevice) CreateSceneSavedSignal() {
+func (b *BaseDevice) CreateSceneSavedSignal(sceneAlias string) {
 	signal := models.SceneSaved{
 		DeviceAlias: b.Alias,
-		SceneAlias:  b.CurrentScene.Alias}
-	b.Signals <- signal
+		SceneAlias:  sceneAlias}
+	b.SendSignal(signal)
 }
 
 // Function frees resources of device entity
 func (b *BaseDevice) Close() {
 	b.StopReconnect <- struct{}{}
 	close(b.StopReconnect)
+	b.StopOutput <- struct{}{}
+	close(b.StopOutput)
+	b.Persister.Flush(b)
 }
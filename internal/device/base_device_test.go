@@ -0,0 +1,178 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/effects"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+)
+
+func TestSetSceneStopsEffectsOutsideScene(t *testing.T) {
+	ctx := context.Background()
+	persister := NewPersister(NewMemoryStore(), 0, zap.NewNop())
+	t.Cleanup(persister.Close)
+	fine := uint16(3)
+	scenes := []SceneConfig{
+		{Alias: "first", ChannelMap: []ChannelMapConfig{{SceneChannelID: 0, UniverseChannelID: 1}, {SceneChannelID: 1, UniverseChannelID: 2}}},
+		{Alias: "second", ChannelMap: []ChannelMapConfig{{SceneChannelID: 0, UniverseChannelID: 2, FineUniverseChannelID: &fine}}},
+	}
+	b := NewBaseDevice(ctx, "test", nil, scenes, nil, 0, 0, make(chan core.Signal, 16), zap.NewNop(), nil, persister)
+	b.Connected.Store(true)
+
+	if err := b.SetScene(ctx, models.SetScene{SceneAlias: "first"}); err != nil {
+		t.Fatalf("SetScene() error = %v", err)
+	}
+	err := b.StartEffect(ctx, models.StartEffect{Channels: []int{0, 1}, Waveform: effects.WaveformSine, Amplitude: 50, PeriodMs: 1000})
+	if err != nil {
+		t.Fatalf("StartEffect() error = %v", err)
+	}
+
+	if err := b.SetScene(ctx, models.SetScene{SceneAlias: "second"}); err != nil {
+		t.Fatalf("SetScene() error = %v", err)
+	}
+	if _, ok := b.Effects.Effects[1]; ok {
+		t.Errorf("effect on channel missing from current scene keeps running")
+	}
+	effect, ok := b.Effects.Effects[2]
+	if !ok {
+		t.Fatalf("effect on channel of current scene is stopped")
+	}
+	if effect.Channel.MaxValue() != 65535 {
+		t.Errorf("effect is not bound to 16-bit channel of current scene")
+	}
+}
+
+func TestSendSignalDoesNotBlockOnFullChannel(t *testing.T) {
+	b := newTestDevice(t)
+	b.Signals = make(chan core.Signal)
+
+	sent := make(chan struct{})
+	go func() {
+		b.SendSignal(models.DeviceInfo{DeviceAlias: b.Alias})
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatalf("SendSignal is blocked by full signal channel")
+	}
+
+	select {
+	case signal := <-b.Signals:
+		if _, ok := signal.(models.DeviceInfo); !ok {
+			t.Errorf("signal = %T, want models.DeviceInfo", signal)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("signal is not delivered once channel has room")
+	}
+}
+
+func TestSetChannelDuringSceneChanges(t *testing.T) {
+	ctx := context.Background()
+	persister := NewPersister(NewMemoryStore(), 0, zap.NewNop())
+	t.Cleanup(persister.Close)
+	scenes := []SceneConfig{
+		{Alias: "first", ChannelMap: []ChannelMapConfig{{SceneChannelID: 0, UniverseChannelID: 1}, {SceneChannelID: 1, UniverseChannelID: 2}}},
+		{Alias: "second", ChannelMap: []ChannelMapConfig{{SceneChannelID: 0, UniverseChannelID: 3}, {SceneChannelID: 1, UniverseChannelID: 4}}},
+	}
+	signals := make(chan core.Signal, 16)
+	b := NewBaseDevice(ctx, "test", nil, scenes, nil, 0, 0, signals, zap.NewNop(), nil, persister)
+	b.Connected.Store(true)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-signals:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	changed := make(chan struct{})
+	go func() {
+		defer close(changed)
+		for i := 0; i < 200; i++ {
+			sceneAlias := "first"
+			if i%2 == 1 {
+				sceneAlias = "second"
+			}
+			if err := b.SetScene(ctx, models.SetScene{SceneAlias: sceneAlias}); err != nil {
+				t.Errorf("SetScene() error = %v", err)
+				return
+			}
+			if err := b.SaveScene(ctx); err != nil {
+				t.Errorf("SaveScene() error = %v", err)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		// channels may be rejected while no scene is selected yet, only data races are checked
+		b.SetChannel(ctx, models.SetChannel{Channel: 0, Value: i % 256})
+		b.IncrementChannel(ctx, models.IncrementChannel{Channel: 1, Value: 1})
+		b.SetChannels(ctx, models.SetChannels{StartChannel: 0, Values: []int{i % 256, 0}})
+	}
+	<-changed
+}
+
+func TestSetUniverseKeepsChannelsInGapsOfPayload(t *testing.T) {
+	b := newTestDevice(t)
+	b.Connected.Store(true)
+	b.AllowSetUniverse = true
+	for i := range b.Universe {
+		b.Universe[i] = 9
+	}
+
+	err := b.SetUniverse(context.Background(), models.SetUniverse{Data: "002003005006006007", Encoding: UniverseEncodingRLE, Start: 10})
+	if err != nil {
+		t.Fatalf("SetUniverse() error = %v", err)
+	}
+	for i, value := range b.Universe {
+		want := byte(9)
+		switch i {
+		case 12, 13:
+			want = 5
+		case 16:
+			want = 7
+		}
+		if value != want {
+			t.Errorf("universe[%d] = %d, want %d", i, value, want)
+		}
+	}
+}
+
+func TestSendSignalDropsSnapshotOnFullChannel(t *testing.T) {
+	b := newTestDevice(t)
+	b.Signals = make(chan core.Signal, 1)
+	b.Signals <- models.DeviceInfo{DeviceAlias: b.Alias}
+
+	b.CreateUniverseSnapshotSignal()
+	b.CreateSceneChangedSignal("scene")
+
+	want := []string{"models.DeviceInfo", "models.SceneChanged"}
+	for _, name := range want {
+		select {
+		case signal := <-b.Signals:
+			if got := fmt.Sprintf("%T", signal); got != name {
+				t.Errorf("signal = %s, want %s", got, name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("signal %s is not delivered", name)
+		}
+	}
+	select {
+	case signal := <-b.Signals:
+		t.Errorf("unexpected signal %T, snapshot should be dropped", signal)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -2,49 +2,56 @@ package device
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
-	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
-// Function reading universe from cache in Redis
-func (b *BaseDevice) ReadUnvierse(ctx context.Context) error {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-	})
+const (
+	UniverseEncodingBase64 = "base64"
+	UniverseEncodingRLE    = "rle"
+
+	CacheFormatPrefix = "v2:" // cached values without prefix are in legacy decimal format
+	noFineChannel     = 0xFFFF
+	sceneRecordSize   = 8
+)
 
+// Function reading universe from cache in store, universe cached in legacy format is rewritten in current format
+func (b *BaseDevice) ReadUnvierse(ctx context.Context) error {
 	key := fmt.Sprintf("%s_universe", b.Alias)
 
-	encodedUniverse, err := rdb.Get(ctx, key).Result()
+	encodedUniverse, err := b.Store.Get(ctx, key)
 	if err != nil {
 		return fmt.Errorf("reading cached universe with key '%s' failed with error: %s", key, err)
 	}
 
-	err = b.DecodeUniverse(encodedUniverse)
+	var universe [512]byte
+	err = DecodeUniverse(encodedUniverse, &universe)
 	if err != nil {
-		b.Universe = [512]byte{}
 		return err
 	}
+	b.Universe = universe
 
+	if IsLegacyCacheSequence(encodedUniverse) {
+		return b.WriteUniverse(ctx)
+	}
 	return nil
 }
 
-// Function writing universe to cache in Redis
+// Function writing universe to cache in store
 func (b *BaseDevice) WriteUniverse(ctx context.Context) error {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-	})
-
 	key := fmt.Sprintf("%s_universe", b.Alias)
-	var encodedUniverse = b.EncodeUniverse()
+	b.Mutex.Lock()
+	var encodedUniverse = EncodeUniverse(&b.Universe)
+	b.Mutex.Unlock()
 
-	_, err := rdb.Set(ctx, key, encodedUniverse, 0).Result()
+	err := b.Store.Set(ctx, key, encodedUniverse)
 	if err != nil {
 		return fmt.Errorf("writing universe with key '%s' to cache failed with error: %s", key, err)
 	}
@@ -52,44 +59,187 @@ func (b *BaseDevice) WriteUniverse(ctx context.Context) error {
 	return nil
 }
 
-// Function reading scenes from cache in Redis
+// Represenation of cached scene index entity, lists scenes created and configured scenes removed at runtime
+type SceneIndex struct {
+	Created []string `json:"created"`
+	Removed []string `json:"removed"`
+}
+
+// Function reading scenes from cache in store, scenes cached in legacy format are rewritten in current format.
+// Configured scenes removed at runtime are dropped and scenes created at runtime are added.
 func (b *BaseDevice) ReadScenes(ctx context.Context) {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-	})
+	migrate := false
 
-	encodedScenesMap := make(map[string]string)
+	err := b.ReadSceneIndex(ctx)
+	if err != nil {
+		b.Logger.Warn("reading scene index from cache failed", zap.Error(err), zap.Any("device", b.Alias))
+	}
+	for sceneAlias := range b.RemovedScenes {
+		delete(b.Scenes, sceneAlias)
+	}
 
-	for sceneAlias := range b.Scenes {
+	for sceneAlias, configuredScene := range b.Scenes {
 		key := fmt.Sprintf("%s_scene_%s", b.Alias, sceneAlias)
-		encodedScene, err := rdb.Get(ctx, key).Result()
+		encodedScene, err := b.Store.Get(ctx, key)
 		if err != nil {
-			b.Logger.Warn(fmt.Sprintf("reading scene '%s' from cache failed", sceneAlias), zap.Error(err), zap.Any("device", b.Alias))
+			if err != ErrNotFound {
+				b.Logger.Warn(fmt.Sprintf("reading scene '%s' from cache failed", sceneAlias), zap.Error(err), zap.Any("device", b.Alias))
+			}
 			continue
 		}
-		encodedScenesMap[sceneAlias] = encodedScene
-	}
 
-	for sceneAlias, encodedScene := range encodedScenesMap {
 		decodedScene := Scene{Alias: sceneAlias, ChannelMap: make(map[int]Channel)}
-		err := b.DecodeScene(encodedScene, decodedScene)
+		if IsLegacyCacheSequence(encodedScene) {
+			err = b.readLegacyScene(ctx, encodedScene, decodedScene)
+			migrate = true
+		} else {
+			err = DecodeScene(encodedScene, decodedScene)
+		}
 		if err != nil {
 			b.Logger.Warn(fmt.Sprintf("decoding cached scene '%s' failed", sceneAlias), zap.Error(err), zap.Any("device", b.Alias))
 			continue
 		}
 
-		err = b.ValidateCachedScene(decodedScene, b.Scenes[sceneAlias])
+		err = b.ValidateCachedScene(decodedScene, configuredScene)
 		if err != nil {
 			b.Logger.Warn(fmt.Sprintf("invalid cached scene '%s'", sceneAlias), zap.Error(err), zap.Any("device", b.Alias))
 		} else {
 			b.Scenes[sceneAlias] = decodedScene
 		}
 	}
+
+	if migrate {
+		b.WriteScenes(ctx)
+	}
+
+	for sceneAlias := range b.CreatedScenes {
+		key := fmt.Sprintf("%s_scene_%s", b.Alias, sceneAlias)
+		encodedScene, err := b.Store.Get(ctx, key)
+		if err != nil {
+			b.Logger.Warn(fmt.Sprintf("reading created scene '%s' from cache failed", sceneAlias), zap.Error(err), zap.Any("device", b.Alias))
+			continue
+		}
+
+		decodedScene := Scene{Alias: sceneAlias, ChannelMap: make(map[int]Channel)}
+		err = DecodeScene(encodedScene, decodedScene)
+		if err != nil {
+			b.Logger.Warn(fmt.Sprintf("decoding created scene '%s' failed", sceneAlias), zap.Error(err), zap.Any("device", b.Alias))
+			continue
+		}
+		b.Scenes[sceneAlias] = decodedScene
+	}
+}
+
+// Function reading index of scenes created and removed at runtime from cache in store
+func (b *BaseDevice) ReadSceneIndex(ctx context.Context) error {
+	key := fmt.Sprintf("%s_scene_index", b.Alias)
+
+	encodedIndex, err := b.Store.Get(ctx, key)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading cached scene index with key '%s' failed with error: %s", key, err)
+	}
+
+	var index SceneIndex
+	err = json.Unmarshal([]byte(encodedIndex), &index)
+	if err != nil {
+		return fmt.Errorf("decoding cached scene index failed: %v", err)
+	}
+	for _, sceneAlias := range index.Created {
+		b.CreatedScenes[sceneAlias] = struct{}{}
+	}
+	for _, sceneAlias := range index.Removed {
+		b.RemovedScenes[sceneAlias] = struct{}{}
+	}
+	return nil
 }
 
-// Function validationg cached scenes in Redis
+// Function writing index of scenes created and removed at runtime to cache in store
+func (b *BaseDevice) WriteSceneIndex(ctx context.Context) error {
+	key := fmt.Sprintf("%s_scene_index", b.Alias)
+
+	b.Mutex.Lock()
+	index := SceneIndex{Created: make([]string, 0, len(b.CreatedScenes)), Removed: make([]string, 0, len(b.RemovedScenes))}
+	for sceneAlias := range b.CreatedScenes {
+		index.Created = append(index.Created, sceneAlias)
+	}
+	for sceneAlias := range b.RemovedScenes {
+		index.Removed = append(index.Removed, sceneAlias)
+	}
+	b.Mutex.Unlock()
+	sort.Strings(index.Created)
+	sort.Strings(index.Removed)
+
+	encodedIndex, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	err = b.Store.Set(ctx, key, string(encodedIndex))
+	if err != nil {
+		return fmt.Errorf("writing scene index with key '%s' to cache failed with error: %s", key, err)
+	}
+	return nil
+}
+
+// Function decoding scene cached in legacy format with its fine channels stored under separate key
+func (b *BaseDevice) readLegacyScene(ctx context.Context, encodedScene string, scene Scene) error {
+	err := DecodeLegacyScene(encodedScene, scene)
+	if err != nil {
+		return err
+	}
+
+	fineKey := fmt.Sprintf("%s_scene_%s_fine", b.Alias, scene.Alias)
+	encodedFineScene, err := b.Store.Get(ctx, fineKey)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading fine channels from cache failed: %v", err)
+	}
+	return DecodeLegacySceneFine(encodedFineScene, scene)
+}
+
+// Function reading alias of current scene from cache in store
+func (b *BaseDevice) ReadCurrentScene(ctx context.Context) error {
+	key := fmt.Sprintf("%s_current_scene", b.Alias)
+
+	sceneAlias, err := b.Store.Get(ctx, key)
+	if err == ErrNotFound || (err == nil && sceneAlias == "") {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading cached current scene with key '%s' failed with error: %s", key, err)
+	}
+
+	scene, ok := b.Scenes[sceneAlias]
+	if !ok {
+		return fmt.Errorf("cached current scene '%s' is not configured", sceneAlias)
+	}
+	b.CurrentScene = &scene
+	return nil
+}
+
+// Function writing alias of current scene to cache in store
+func (b *BaseDevice) WriteCurrentScene(ctx context.Context) error {
+	key := fmt.Sprintf("%s_current_scene", b.Alias)
+	b.Mutex.Lock()
+	sceneAlias := ""
+	if b.CurrentScene != nil {
+		sceneAlias = b.CurrentScene.Alias
+	}
+	b.Mutex.Unlock()
+
+	err := b.Store.Set(ctx, key, sceneAlias)
+	if err != nil {
+		return fmt.Errorf("writing current scene with key '%s' to cache failed with error: %s", key, err)
+	}
+
+	return nil
+}
+
+// Function validationg cached scenes in store
 func (b *BaseDevice) ValidateCachedScene(cachedScene Scene, configuredScene Scene) error {
 	if len(cachedScene.ChannelMap) != len(configuredScene.ChannelMap) {
 		return fmt.Errorf("unequal channelMap sizes")
@@ -104,36 +254,249 @@ func (b *BaseDevice) ValidateCachedScene(cachedScene Scene, configuredScene Scen
 			return fmt.Errorf("cached UniverseChanneldID is not equal to configured UniverseChannelID ('%d' != '%d')",
 				cachedChannel.UniverseChannelID, configuredChannel.UniverseChannelID)
 		}
+		if configuredChannel.Wide != cachedChannel.Wide || configuredChannel.FineUniverseChannelID != cachedChannel.FineUniverseChannelID {
+			return fmt.Errorf("cached fine channel of scene channel '%d' is not equal to configured one", cachedKey)
+		}
 	}
-	
+
 	return nil
 }
 
-// Function writing scenes to cache in Redis
+// Function writing scenes to cache in store
 func (b *BaseDevice) WriteScenes(ctx context.Context) {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-	})
-
+	b.Mutex.Lock()
+	scenes := make(map[string]Scene, len(b.Scenes))
 	for sceneAlias, scene := range b.Scenes {
+		scenes[sceneAlias] = scene
+	}
+	b.Mutex.Unlock()
 
-		key := fmt.Sprintf("%s_scene_%s", b.Alias, sceneAlias)
-		var encodedScene = b.EncodeScene(scene)
-
-		_, err := rdb.Set(ctx, key, encodedScene, 0).Result()
+	for sceneAlias, scene := range scenes {
+		err := b.WriteScene(ctx, scene)
 		if err != nil {
 			b.Logger.Warn(fmt.Sprintf("writing scene '%s' to cache failed", sceneAlias), zap.Error(err), zap.Any("device", b.Alias))
 		}
 	}
 }
 
-// Function decoding cached universe in Redis
-func (b *BaseDevice) DecodeUniverse(sequence string) error {
+// Function writing single scene to cache in store, channel map of scene is encoded under device mutex
+func (b *BaseDevice) WriteScene(ctx context.Context, scene Scene) error {
+	key := fmt.Sprintf("%s_scene_%s", b.Alias, scene.Alias)
+	b.Mutex.Lock()
+	var encodedScene = EncodeScene(scene)
+	b.Mutex.Unlock()
+
+	return b.Store.Set(ctx, key, encodedScene)
+}
+
+// Function deleting single scene from cache in store, legacy fine channels are deleted too
+func (b *BaseDevice) DeleteCachedScene(ctx context.Context, sceneAlias string) error {
+	key := fmt.Sprintf("%s_scene_%s", b.Alias, sceneAlias)
+	err := b.Store.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+	return b.Store.Delete(ctx, key+"_fine")
+}
+
+// Function reports whether cached value is in legacy decimal format
+func IsLegacyCacheSequence(sequence string) bool {
+	return !strings.HasPrefix(sequence, CacheFormatPrefix)
+}
+
+// Function encoding universe as runs of equal values, each run is 2-byte big-endian length and value byte
+func EncodeUniverse(universe *[512]byte) string {
+	data := make([]byte, 0, 48)
+	start := 0
+	for idx := 1; idx <= 512; idx++ {
+		if idx < 512 && universe[idx] == universe[start] {
+			continue
+		}
+		data = binary.BigEndian.AppendUint16(data, uint16(idx-start))
+		data = append(data, universe[start])
+		start = idx
+	}
+
+	return CacheFormatPrefix + base64.StdEncoding.EncodeToString(data)
+}
+
+// Function decoding universe encoded in current or legacy format
+func DecodeUniverse(sequence string, universe *[512]byte) error {
+	if IsLegacyCacheSequence(sequence) {
+		return DecodeLegacyUniverse(sequence, universe)
+	}
+
+	values, err := decodeUniverseRuns(sequence)
+	if err != nil {
+		return err
+	}
+	if len(values) != 512 {
+		return fmt.Errorf("got incomplete universe of '%d' channels", len(values))
+	}
+
+	copy(universe[:], values)
+	return nil
+}
+
+// Function decoding runs of universe encoded in current format into channel values, runs cover at most 512 channels
+func decodeUniverseRuns(sequence string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sequence, CacheFormatPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("got invalid universe encoding: %v", err)
+	}
+	if len(data)%3 != 0 {
+		return nil, fmt.Errorf("got invalid universe size")
+	}
+
+	values := make([]byte, 0, 512)
+	for i := 0; i < len(data); i += 3 {
+		length := int(binary.BigEndian.Uint16(data[i : i+2]))
+		if length == 0 || len(values)+length > 512 {
+			return nil, fmt.Errorf("got invalid universe run length '%d' at channel '%d'", length, len(values))
+		}
+		for j := 0; j < length; j++ {
+			values = append(values, data[i+2])
+		}
+	}
+	return values, nil
+}
+
+// Function decoding channel values of universe upload starting from start channel.
+// Base64 payload holds raw values, RLE payload is universe encoding of cache (current or legacy format)
+// covering as many channels as its runs do, values are shifted by start channel and must fit in universe.
+// Returned mask reports which values are set by payload, channels in gaps between legacy runs are not set.
+func DecodeUniversePayload(data string, encoding string, start int) ([]byte, []bool, error) {
+	if start < 0 || start > 511 {
+		return nil, nil, fmt.Errorf("start channel '%d' out of range [0:511]", start)
+	}
+
+	var values []byte
+	var covered []bool
+	switch encoding {
+	case "", UniverseEncodingBase64:
+		var err error
+		values, err = base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("got invalid base64 payload: %v", err)
+		}
+	case UniverseEncodingRLE:
+		if IsLegacyCacheSequence(data) {
+			var universe [512]byte
+			var mask [512]bool
+			length, err := decodeLegacyUniverse(data, &universe, &mask)
+			if err != nil {
+				return nil, nil, err
+			}
+			values = universe[:length]
+			covered = mask[:length]
+		} else {
+			var err error
+			values, err = decodeUniverseRuns(data)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown universe encoding '%s' (expected '%s' or '%s')", encoding, UniverseEncodingBase64, UniverseEncodingRLE)
+	}
+
+	if len(values) == 0 {
+		return nil, nil, fmt.Errorf("no channel values specified")
+	}
+	if start+len(values) > 512 {
+		return nil, nil, fmt.Errorf("'%d' values starting from channel '%d' exceed universe size", len(values), start)
+	}
+	if covered == nil {
+		covered = make([]bool, len(values))
+		for idx := range covered {
+			covered[idx] = true
+		}
+	}
+	return values, covered, nil
+}
+
+// Function encoding scene channels ordered by scene channel ID, each channel is 2-byte big-endian
+// scene channel ID, universe channel ID, fine universe channel ID (0xFFFF for 8-bit channel) and value
+func EncodeScene(scene Scene) string {
+	sceneChannelIDs := make([]int, 0, len(scene.ChannelMap))
+	for sceneChannelID := range scene.ChannelMap {
+		sceneChannelIDs = append(sceneChannelIDs, sceneChannelID)
+	}
+	sort.Ints(sceneChannelIDs)
+
+	data := make([]byte, 0, len(sceneChannelIDs)*sceneRecordSize)
+	for _, sceneChannelID := range sceneChannelIDs {
+		channel := scene.ChannelMap[sceneChannelID]
+		fineUniverseChannelID := noFineChannel
+		if channel.Wide {
+			fineUniverseChannelID = channel.FineUniverseChannelID
+		}
+		data = binary.BigEndian.AppendUint16(data, uint16(sceneChannelID))
+		data = binary.BigEndian.AppendUint16(data, uint16(channel.UniverseChannelID))
+		data = binary.BigEndian.AppendUint16(data, uint16(fineUniverseChannelID))
+		data = binary.BigEndian.AppendUint16(data, uint16(channel.Value))
+	}
+
+	return CacheFormatPrefix + base64.StdEncoding.EncodeToString(data)
+}
+
+// Function decoding scene encoded in current format into channel map of scene
+func DecodeScene(sequence string, scene Scene) error {
+	if IsLegacyCacheSequence(sequence) {
+		return DecodeLegacyScene(sequence, scene)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sequence, CacheFormatPrefix))
+	if err != nil {
+		return fmt.Errorf("got invalid scene encoding: %v", err)
+	}
+	if len(data)%sceneRecordSize != 0 {
+		return fmt.Errorf("got invalid scene size")
+	}
+
+	for i := 0; i < len(data); i += sceneRecordSize {
+		sceneChannelID := int(binary.BigEndian.Uint16(data[i : i+2]))
+		channel := Channel{
+			UniverseChannelID:     int(binary.BigEndian.Uint16(data[i+2 : i+4])),
+			FineUniverseChannelID: int(binary.BigEndian.Uint16(data[i+4 : i+6])),
+			Value:                 int(binary.BigEndian.Uint16(data[i+6 : i+8])),
+		}
+		channel.Wide = channel.FineUniverseChannelID != noFineChannel
+		if !channel.Wide {
+			channel.FineUniverseChannelID = 0
+		}
+
+		if sceneChannelID > 511 {
+			return fmt.Errorf("scene channel out of range [0:511]")
+		}
+		if channel.UniverseChannelID > 511 || channel.FineUniverseChannelID > 511 {
+			return fmt.Errorf("universe channel out of range [0:511]")
+		}
+		if channel.Value > channel.MaxValue() {
+			return fmt.Errorf("channel value out of range [0:%d]", channel.MaxValue())
+		}
+		if _, ok := scene.ChannelMap[sceneChannelID]; ok {
+			return fmt.Errorf("duplicated scene channel '%d'", sceneChannelID)
+		}
+
+		scene.ChannelMap[sceneChannelID] = channel
+	}
+
+	return nil
+}
+
+// Function decoding universe in legacy fixed-width decimal RLE format ("%03d%03d%03d" per run)
+func DecodeLegacyUniverse(sequence string, universe *[512]byte) error {
+	_, err := decodeLegacyUniverse(sequence, universe, nil)
+	return err
+}
+
+// Function decoding universe in legacy format, returns number of channels up to last channel of last run.
+// Channels covered by runs are marked in covered if it is not nil.
+func decodeLegacyUniverse(sequence string, universe *[512]byte, covered *[512]bool) (int, error) {
 	size := len(sequence)
-	if size % 9 != 0 {
-		return fmt.Errorf("got invalid RLE sequence size")
+	if size%9 != 0 {
+		return 0, fmt.Errorf("got invalid RLE sequence size")
 	}
 
 	previousLastChannel := -1
@@ -141,80 +504,75 @@ func (b *BaseDevice) DecodeUniverse(sequence string) error {
 		subsequence := sequence[i : i+9]
 		initialChannel, err := strconv.Atoi(subsequence[0:3])
 		if err != nil {
-			return err
+			return 0, err
 		}
 		lastChannel, err := strconv.Atoi(subsequence[3:6])
 		if err != nil {
-			return err
+			return 0, err
 		}
 		channelValue, err := strconv.Atoi(subsequence[6:9])
 		if err != nil {
-			return err
+			return 0, err
 		}
 		if initialChannel > lastChannel {
-			return fmt.Errorf("got invalid RLE sequence (initialChannel > lastChannel)")
+			return 0, fmt.Errorf("got invalid RLE sequence (initialChannel > lastChannel)")
 		}
 
 		if initialChannel < 0 || initialChannel > 511 {
-			return fmt.Errorf("got invalid RLE sequence (initialChannel out of range [0:511])")
+			return 0, fmt.Errorf("got invalid RLE sequence (initialChannel out of range [0:511])")
 		}
 
 		if lastChannel < 0 || lastChannel > 511 {
-			return fmt.Errorf("got invalid RLE sequence (lastChannel out of range [0:511])")
+			return 0, fmt.Errorf("got invalid RLE sequence (lastChannel out of range [0:511])")
 		}
 
 		if channelValue < 0 || channelValue > 255 {
-			return fmt.Errorf("got invalid RLE sequence (channelValue out of range [0:255])")
+			return 0, fmt.Errorf("got invalid RLE sequence (channelValue out of range [0:255])")
 		}
 
 		if previousLastChannel >= initialChannel {
-			return fmt.Errorf("got invalid RLE sequence (previousLastChannel > currentInitialChannel)")
+			return 0, fmt.Errorf("got invalid RLE sequence (previousLastChannel > currentInitialChannel)")
 		}
 
 		for j := initialChannel; j <= lastChannel; j++ {
-			b.Universe[j] = byte(channelValue)
+			universe[j] = byte(channelValue)
+			if covered != nil {
+				covered[j] = true
+			}
 		}
 
 		previousLastChannel = lastChannel
 	}
 
-	return nil
+	return previousLastChannel + 1, nil
 }
 
-// Function encoding universe to cache in Redis
-func (b *BaseDevice) EncodeUniverse() string {
-	var result string
-	var currentChannelValue int = int(b.Universe[0])
-	var sequenceStart int = 0
-	for idx, channel := range b.Universe {
-		if currentChannelValue != int(channel) {
-			result += fmt.Sprintf("%03d", sequenceStart) + fmt.Sprintf("%03d", idx-1) + fmt.Sprintf("%03d", currentChannelValue)
-			currentChannelValue = int(channel)
-			sequenceStart = idx
-		}
-		if idx == 511 {
-			result += fmt.Sprintf("%03d", sequenceStart) + fmt.Sprintf("%03d", idx) + fmt.Sprintf("%03d", currentChannelValue)
-		}
+// Function decoding fine (LSB) channels of scene in legacy format, coarse channels must be decoded already
+func DecodeLegacySceneFine(sequence string, scene Scene) error {
+	fineScene := Scene{Alias: scene.Alias, ChannelMap: make(map[int]Channel)}
+	err := DecodeLegacyScene(sequence, fineScene)
+	if err != nil {
+		return err
 	}
 
-	return result
-}
-
-// Function encoding scene to cache in Redis
-func (b *BaseDevice) EncodeScene(scene Scene) string {
-	var result string
-
-	for sceneChannelID, channel := range scene.ChannelMap {
-		result += fmt.Sprintf("%03d", sceneChannelID) + fmt.Sprintf("%03d", channel.UniverseChannelID) + fmt.Sprintf("%03d", channel.Value)
+	for sceneChannelID, fineChannel := range fineScene.ChannelMap {
+		channel, ok := scene.ChannelMap[sceneChannelID]
+		if !ok {
+			return fmt.Errorf("fine channel of unknown scene channel '%d'", sceneChannelID)
+		}
+		channel.Wide = true
+		channel.FineUniverseChannelID = fineChannel.UniverseChannelID
+		channel.Value = channel.Value<<8 | fineChannel.Value
+		scene.ChannelMap[sceneChannelID] = channel
 	}
 
-	return result
+	return nil
 }
 
-// Function decoding cached scene in Redis
-func (b *BaseDevice) DecodeScene(sequence string, scene Scene) (error) {
+// Function decoding scene in legacy fixed-width decimal format ("%03d%03d%03d" per channel)
+func DecodeLegacyScene(sequence string, scene Scene) error {
 	size := len(sequence)
-	if size % 9 != 0 {
+	if size%9 != 0 {
 		return fmt.Errorf("got invalid RLE sequence size")
 	}
 
@@ -250,4 +608,4 @@ func (b *BaseDevice) DecodeScene(sequence string, scene Scene) (error) {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
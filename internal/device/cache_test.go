@@ -0,0 +1,361 @@
+package device
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+)
+
+func TestUniverseRoundTrip(t *testing.T) {
+	var ramp [512]byte
+	for i := range ramp {
+		ramp[i] = byte(i)
+	}
+	var runs [512]byte
+	for i := 100; i < 200; i++ {
+		runs[i] = 255
+	}
+	runs[511] = 1
+
+	tests := []struct {
+		name     string
+		universe [512]byte
+	}{
+		{name: "blackout", universe: [512]byte{}},
+		{name: "runs", universe: runs},
+		{name: "every channel differs", universe: ramp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := EncodeUniverse(&tt.universe)
+			if IsLegacyCacheSequence(encoded) {
+				t.Fatalf("encoded universe %q has no version prefix", encoded)
+			}
+
+			var decoded [512]byte
+			err := DecodeUniverse(encoded, &decoded)
+			if err != nil {
+				t.Fatalf("DecodeUniverse() error = %v", err)
+			}
+			if decoded != tt.universe {
+				t.Errorf("decoded universe differs from encoded one")
+			}
+		})
+	}
+}
+
+func TestSceneRoundTrip(t *testing.T) {
+	scene := Scene{Alias: "scene", ChannelMap: map[int]Channel{
+		0: {UniverseChannelID: 10, Value: 255},
+		1: {UniverseChannelID: 11, FineUniverseChannelID: 12, Wide: true, Value: 65535},
+		5: {UniverseChannelID: 511, Value: 0},
+	}}
+
+	decoded := Scene{Alias: "scene", ChannelMap: make(map[int]Channel)}
+	err := DecodeScene(EncodeScene(scene), decoded)
+	if err != nil {
+		t.Fatalf("DecodeScene() error = %v", err)
+	}
+	if len(decoded.ChannelMap) != len(scene.ChannelMap) {
+		t.Fatalf("decoded %d channels, want %d", len(decoded.ChannelMap), len(scene.ChannelMap))
+	}
+	for sceneChannelID, channel := range scene.ChannelMap {
+		if decoded.ChannelMap[sceneChannelID] != channel {
+			t.Errorf("channel %d = %+v, want %+v", sceneChannelID, decoded.ChannelMap[sceneChannelID], channel)
+		}
+	}
+}
+
+func TestDecodeLegacyUniverse(t *testing.T) {
+	var universe [512]byte
+	err := DecodeUniverse("000009255010511007", &universe)
+	if err != nil {
+		t.Fatalf("DecodeUniverse() error = %v", err)
+	}
+	for i, value := range universe {
+		want := byte(7)
+		if i < 10 {
+			want = 255
+		}
+		if value != want {
+			t.Fatalf("universe[%d] = %d, want %d", i, value, want)
+		}
+	}
+}
+
+func TestDecodeLegacyScene(t *testing.T) {
+	scene := Scene{Alias: "scene", ChannelMap: make(map[int]Channel)}
+	err := DecodeScene("000010128001011200", scene)
+	if err != nil {
+		t.Fatalf("DecodeScene() error = %v", err)
+	}
+	err = DecodeLegacySceneFine("001012034", scene)
+	if err != nil {
+		t.Fatalf("DecodeLegacySceneFine() error = %v", err)
+	}
+
+	want := map[int]Channel{
+		0: {UniverseChannelID: 10, Value: 128},
+		1: {UniverseChannelID: 11, FineUniverseChannelID: 12, Wide: true, Value: 200<<8 | 34},
+	}
+	for sceneChannelID, channel := range want {
+		if scene.ChannelMap[sceneChannelID] != channel {
+			t.Errorf("channel %d = %+v, want %+v", sceneChannelID, scene.ChannelMap[sceneChannelID], channel)
+		}
+	}
+}
+
+func TestDecodeUniverseRejectsInvalid(t *testing.T) {
+	v2 := func(data ...byte) string {
+		return CacheFormatPrefix + base64.StdEncoding.EncodeToString(data)
+	}
+
+	tests := []struct {
+		name     string
+		sequence string
+	}{
+		{name: "size is not multiple of run", sequence: v2(0x02, 0x00, 0, 1)},
+		{name: "incomplete universe", sequence: v2(0x01, 0x00, 0)},
+		{name: "run beyond universe", sequence: v2(0x02, 0x01, 0)},
+		{name: "zero run", sequence: v2(0x00, 0x00, 0, 0x02, 0x00, 0)},
+		{name: "invalid base64", sequence: CacheFormatPrefix + "!"},
+		{name: "unknown version", sequence: "v3:AgAA"},
+		{name: "legacy size", sequence: "0000092550"},
+		{name: "legacy channel out of range", sequence: "000512000"},
+		{name: "legacy overlapping runs", sequence: "000009255005511007"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var universe [512]byte
+			if err := DecodeUniverse(tt.sequence, &universe); err == nil {
+				t.Errorf("DecodeUniverse(%q) succeeded, want error", tt.sequence)
+			}
+		})
+	}
+}
+
+func TestDecodeSceneRejectsInvalid(t *testing.T) {
+	v2 := func(data ...byte) string {
+		return CacheFormatPrefix + base64.StdEncoding.EncodeToString(data)
+	}
+
+	tests := []struct {
+		name     string
+		sequence string
+	}{
+		{name: "size is not multiple of record", sequence: v2(0, 0, 0, 10, 0xFF, 0xFF, 0)},
+		{name: "value out of range", sequence: v2(0, 0, 0, 10, 0xFF, 0xFF, 0x01, 0x00)},
+		{name: "universe channel out of range", sequence: v2(0, 0, 0x02, 0x00, 0xFF, 0xFF, 0, 1)},
+		{name: "duplicated channel", sequence: v2(0, 0, 0, 10, 0xFF, 0xFF, 0, 1, 0, 0, 0, 11, 0xFF, 0xFF, 0, 1)},
+		{name: "unknown version", sequence: "v3:AAAACv//AAE="},
+		{name: "legacy size", sequence: "00001012"},
+		{name: "legacy value out of range", sequence: "000010256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scene := Scene{Alias: "scene", ChannelMap: make(map[int]Channel)}
+			if err := DecodeScene(tt.sequence, scene); err == nil {
+				t.Errorf("DecodeScene(%q) succeeded, want error", tt.sequence)
+			}
+		})
+	}
+}
+
+func TestReadCacheMigratesLegacyFormat(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	persister := NewPersister(store, 0, zap.NewNop())
+	t.Cleanup(persister.Close)
+
+	store.Set(ctx, "test_universe", "000511007")
+	store.Set(ctx, "test_scene_scene", "000010128")
+	scenes := []SceneConfig{{Alias: "scene", ChannelMap: []ChannelMapConfig{{SceneChannelID: 0, UniverseChannelID: 10}}}}
+
+	b := NewBaseDevice(ctx, "test", nil, scenes, nil, 0, 0, make(chan core.Signal, 16), zap.NewNop(), nil, persister)
+
+	if b.Universe[0] != 7 || b.Universe[511] != 7 {
+		t.Errorf("legacy universe is not restored")
+	}
+	if b.Scenes["scene"].ChannelMap[0].Value != 128 {
+		t.Errorf("legacy scene is not restored")
+	}
+	for _, key := range []string{"test_universe", "test_scene_scene"} {
+		value, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("reading %s failed: %v", key, err)
+		}
+		if !strings.HasPrefix(value, CacheFormatPrefix) {
+			t.Errorf("%s = %q is not rewritten in current format", key, value)
+		}
+	}
+}
+
+func TestDecodeUniversePayload(t *testing.T) {
+	v2 := func(data ...byte) string {
+		return CacheFormatPrefix + base64.StdEncoding.EncodeToString(data)
+	}
+	var full [512]byte
+	full[0] = 9
+
+	tests := []struct {
+		name     string
+		data     string
+		encoding string
+		start    int
+		want     []byte
+		gaps     []int // indices of values not set by payload
+		wantErr  bool
+	}{
+		{name: "base64 values", data: base64.StdEncoding.EncodeToString([]byte{1, 2, 3}), start: 509, want: []byte{1, 2, 3}},
+		{name: "base64 beyond universe", data: base64.StdEncoding.EncodeToString([]byte{1, 2, 3}), start: 510, wantErr: true},
+		{name: "partial rle", data: v2(0x00, 0x02, 7, 0x00, 0x01, 8), encoding: UniverseEncodingRLE, start: 100, want: []byte{7, 7, 8}},
+		{name: "partial rle at last channels", data: v2(0x00, 0x02, 7), encoding: UniverseEncodingRLE, start: 510, want: []byte{7, 7}},
+		{name: "rle beyond universe", data: v2(0x00, 0x03, 7), encoding: UniverseEncodingRLE, start: 510, wantErr: true},
+		{name: "full rle universe", data: EncodeUniverse(&full), encoding: UniverseEncodingRLE, want: full[:]},
+		{name: "full rle universe shifted", data: EncodeUniverse(&full), encoding: UniverseEncodingRLE, start: 1, wantErr: true},
+		{name: "empty rle", data: v2(), encoding: UniverseEncodingRLE, wantErr: true},
+		{name: "legacy rle", data: "000001005", encoding: UniverseEncodingRLE, start: 10, want: []byte{5, 5}},
+		{name: "gapped legacy rle", data: "002003005006006007", encoding: UniverseEncodingRLE, start: 10, want: []byte{0, 0, 5, 5, 0, 0, 7}, gaps: []int{0, 1, 4, 5}},
+		{name: "unknown encoding", data: "AAAA", encoding: "hex", wantErr: true},
+		{name: "start out of range", data: v2(0x00, 0x01, 7), encoding: UniverseEncodingRLE, start: 512, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, covered, err := DecodeUniversePayload(tt.data, tt.encoding, tt.start)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("DecodeUniversePayload() = %v, want error", values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeUniversePayload() error = %v", err)
+			}
+			if string(values) != string(tt.want) {
+				t.Errorf("DecodeUniversePayload() = %v, want %v", values, tt.want)
+			}
+			if len(covered) != len(values) {
+				t.Fatalf("mask of %d values is returned for %d values", len(covered), len(values))
+			}
+			gaps := make(map[int]bool)
+			for _, idx := range tt.gaps {
+				gaps[idx] = true
+			}
+			for idx := range covered {
+				if covered[idx] == gaps[idx] {
+					t.Errorf("value %d is set by payload = %v, want %v", idx, covered[idx], !gaps[idx])
+				}
+			}
+		})
+	}
+}
+
+func FuzzDecodeUniverse(f *testing.F) {
+	var ramp [512]byte
+	for i := range ramp {
+		ramp[i] = byte(i)
+	}
+	f.Add(EncodeUniverse(&ramp))
+	f.Add(EncodeUniverse(&[512]byte{}))
+	f.Add(CacheFormatPrefix + base64.StdEncoding.EncodeToString([]byte{0x01, 0xFF, 7, 0x00, 0x01, 8}))
+	f.Add("000009255010511007")
+	f.Add("000511000")
+	f.Add("000009255005511007")
+	f.Add(CacheFormatPrefix + "!")
+
+	f.Fuzz(func(t *testing.T, sequence string) {
+		var universe [512]byte
+		err := DecodeUniverse(sequence, &universe)
+		if err != nil {
+			return
+		}
+
+		var decoded [512]byte
+		err = DecodeUniverse(EncodeUniverse(&universe), &decoded)
+		if err != nil {
+			t.Fatalf("decoding of encoded universe failed: %v", err)
+		}
+		if decoded != universe {
+			t.Fatalf("universe decoded from %q differs after encode and decode", sequence)
+		}
+	})
+}
+
+func FuzzDecodeUniversePayload(f *testing.F) {
+	var full [512]byte
+	full[0] = 9
+	f.Add(EncodeUniverse(&full), UniverseEncodingRLE, 0)
+	f.Add(CacheFormatPrefix+base64.StdEncoding.EncodeToString([]byte{0x00, 0x02, 7, 0x00, 0x01, 8}), UniverseEncodingRLE, 100)
+	f.Add("000001005", UniverseEncodingRLE, 10)
+	f.Add("000001005010011007", UniverseEncodingRLE, 0)
+	f.Add(base64.StdEncoding.EncodeToString([]byte{1, 2, 3}), UniverseEncodingBase64, 509)
+	f.Add("AAAA", "hex", 0)
+
+	f.Fuzz(func(t *testing.T, data string, encoding string, start int) {
+		values, covered, err := DecodeUniversePayload(data, encoding, start)
+		if err != nil {
+			return
+		}
+		if len(values) == 0 || start < 0 || start+len(values) > 512 {
+			t.Fatalf("DecodeUniversePayload() = %d values from channel %d, want values within universe", len(values), start)
+		}
+		if len(covered) != len(values) || !covered[len(covered)-1] {
+			t.Fatalf("mask %v doesn't cover last value of payload", covered)
+		}
+		if encoding != UniverseEncodingRLE || IsLegacyCacheSequence(data) {
+			return
+		}
+
+		var universe [512]byte
+		copy(universe[:], values)
+		decoded, err := decodeUniverseRuns(EncodeUniverse(&universe))
+		if err != nil {
+			t.Fatalf("decoding of encoded universe failed: %v", err)
+		}
+		if string(decoded[:len(values)]) != string(values) {
+			t.Fatalf("payload values decoded from %q differ after encode and decode", data)
+		}
+	})
+}
+
+func FuzzDecodeScene(f *testing.F) {
+	f.Add(EncodeScene(Scene{ChannelMap: map[int]Channel{
+		0: {UniverseChannelID: 10, Value: 255},
+		1: {UniverseChannelID: 11, FineUniverseChannelID: 12, Wide: true, Value: 65535},
+	}}))
+	f.Add(EncodeScene(Scene{ChannelMap: map[int]Channel{}}))
+	f.Add(CacheFormatPrefix + base64.StdEncoding.EncodeToString([]byte{0, 0, 0x02, 0x00, 0xFF, 0xFF, 0, 1}))
+	f.Add("000010128001011200")
+	f.Add("000010256")
+	f.Add("v3:AAAACv//AAE=")
+
+	f.Fuzz(func(t *testing.T, sequence string) {
+		scene := Scene{Alias: "scene", ChannelMap: make(map[int]Channel)}
+		err := DecodeScene(sequence, scene)
+		if err != nil {
+			return
+		}
+
+		decoded := Scene{Alias: "scene", ChannelMap: make(map[int]Channel)}
+		err = DecodeScene(EncodeScene(scene), decoded)
+		if err != nil {
+			t.Fatalf("decoding of encoded scene failed: %v", err)
+		}
+		if len(decoded.ChannelMap) != len(scene.ChannelMap) {
+			t.Fatalf("decoded %d channels, want %d", len(decoded.ChannelMap), len(scene.ChannelMap))
+		}
+		for sceneChannelID, channel := range scene.ChannelMap {
+			if decoded.ChannelMap[sceneChannelID] != channel {
+				t.Fatalf("channel %d = %+v, want %+v", sceneChannelID, decoded.ChannelMap[sceneChannelID], channel)
+			}
+		}
+	})
+}
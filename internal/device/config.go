@@ -3,16 +3,57 @@ package device
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
 )
 
 const (
-	DefaultReconnectInterval = 1500
+	DefaultReconnectInterval       = 1500
+	DefaultFrameRate               = 30
+	DefaultArtNetKeepAliveInterval = 1000
+	DefaultSACNKeepAliveInterval   = 1000
+	MinDMXBreakTime                = 96   // us, 9 widget time units
+	MinDMXMABTime                  = 11   // us, 1 widget time unit
+	MaxDMXWidgetTime               = 1355 // us, 127 widget time units
+	MaxDMXWidgetRefreshRate        = 40
+	DefaultDMXSlots                = 512
+	MinDMXSlots                    = 24
+	MaxDMXReadTimeout              = 25500 // ms, limited by termios VTIME
+	DMXDriverEnttecPro             = "enttec_pro"
+	DMXDriverOpenDMX               = "open_dmx"
+	VirtualDumpLog                 = "log"
+	VirtualDumpFile                = "file"
+	DefaultFlushInterval           = 1000 // ms
 )
 
-// Represenation of channel map entity
+// Represenation of channel map entity, fixture attribute ("fixture.attribute") takes precedence over universe channel
 type ChannelMapConfig struct {
-	SceneChannelID    uint16 `json:"scene_channel_id" yaml:"scene_channel_id"`
-	UniverseChannelID uint16 `json:"universe_channel_id" yaml:"universe_channel_id"`
+	SceneChannelID        uint16  `json:"scene_channel_id" yaml:"scene_channel_id"`
+	UniverseChannelID     uint16  `json:"universe_channel_id" yaml:"universe_channel_id"`
+	FineUniverseChannelID *uint16 `json:"fine_universe_channel_id" yaml:"fine_universe_channel_id"` // optional, makes channel 16-bit
+	FixtureAttribute      string  `json:"fixture_attribute" yaml:"fixture_attribute"`
+	Value                 int     `json:"value" yaml:"value"` // optional, initial value, [0:255], [0:65535] for 16-bit channel
+}
+
+// Represenation of fixture profile attribute entity
+type FixtureAttributeConfig struct {
+	Name       string `json:"name" yaml:"name"`
+	Offset     int    `json:"offset" yaml:"offset"`
+	FineOffset *int   `json:"fine_offset" yaml:"fine_offset"` // optional, makes attribute 16-bit
+}
+
+// Represenation of fixture profile configuration entity
+type FixtureProfileConfig struct {
+	Alias      string                   `json:"alias" yaml:"alias"`
+	Attributes []FixtureAttributeConfig `json:"attributes" yaml:"attributes"`
+}
+
+// Represenation of patched fixture configuration entity of device
+type FixtureConfig struct {
+	Alias        string `json:"alias" yaml:"alias"`
+	Profile      string `json:"profile" yaml:"profile"`
+	StartAddress int    `json:"start_address" yaml:"start_address"`
 }
 
 // Represenation of scene configuration entity
@@ -21,49 +62,268 @@ type SceneConfig struct {
 	ChannelMap []ChannelMapConfig `json:"channel_map" yaml:"channel_map"`
 }
 
+// Represenation of Artnet controller configuration entity in user configuration
+type ArtNetControllerConfig struct {
+	BindAddress      string `json:"bind_address" yaml:"bind_address"`
+	BroadcastAddress string `json:"broadcast_address" yaml:"broadcast_address"`
+	InputAddress     string `json:"input_address" yaml:"input_address"`
+}
+
+// Represenation of Artnet input configuration entity in user configuration
+type ArtNetInputConfig struct {
+	DeviceAlias string `json:"device_alias" yaml:"device_alias"`
+	Net         int    `json:"net" yaml:"net"`
+	SubUni      int    `json:"subuni" yaml:"subuni"`
+	Merge       string `json:"merge" yaml:"merge"`     // "ltp" or "htp" (default)
+	Timeout     int    `json:"timeout" yaml:"timeout"` // ms, HTP input is ignored after timeout
+}
+
 // Represenation of Artnet device configuration entity in user configuration
 type ArtNetConfig struct {
-	Alias               string        `json:"alias" yaml:"alias"`
-	Net                 int           `json:"net" yaml:"net"`
-	SubUni              int           `json:"subuni" yaml:"subuni"`
-	Scenes              []SceneConfig `json:"scenes" yaml:"scenes"`
-	NonBlackoutChannels []int         `json:"non_blackout_channels" yaml:"non_blackout_channels"`
-	ReconnectInterval   int           `json:"reconnect_interval" yaml:"reconnect_interval"`
+	Alias               string          `json:"alias" yaml:"alias"`
+	IP                  string          `json:"ip" yaml:"ip"` // optional, unicast node address, ArtPoll discovery is used if empty
+	Net                 int             `json:"net" yaml:"net"`
+	SubUni              int             `json:"subuni" yaml:"subuni"`
+	Fixtures            []FixtureConfig `json:"fixtures" yaml:"fixtures"`
+	Scenes              []SceneConfig   `json:"scenes" yaml:"scenes"`
+	NonBlackoutChannels []int           `json:"non_blackout_channels" yaml:"non_blackout_channels"`
+	AllowSetUniverse    bool            `json:"allow_set_universe" yaml:"allow_set_universe"` // optional, enables SetUniverse command
+	ProtectedChannels   []int           `json:"protected_channels" yaml:"protected_channels"` // optional, universe channels never written by SetUniverse
+	ReconnectInterval   int             `json:"reconnect_interval" yaml:"reconnect_interval"`
+	FrameRate           int             `json:"frame_rate" yaml:"frame_rate"`
+	KeepAliveInterval   int             `json:"keep_alive_interval" yaml:"keep_alive_interval"`
+}
+
+// Represenation of sACN (E1.31) device configuration entity in user configuration
+type SACNConfig struct {
+	Alias               string          `json:"alias" yaml:"alias"`
+	Universe            int             `json:"universe" yaml:"universe"`
+	Priority            int             `json:"priority" yaml:"priority"`
+	SourceName          string          `json:"source_name" yaml:"source_name"`
+	CID                 string          `json:"cid" yaml:"cid"`
+	Destination         string          `json:"destination" yaml:"destination"` // unicast IP, multicast is used if empty
+	Port                int             `json:"port" yaml:"port"`
+	Fixtures            []FixtureConfig `json:"fixtures" yaml:"fixtures"`
+	Scenes              []SceneConfig   `json:"scenes" yaml:"scenes"`
+	NonBlackoutChannels []int           `json:"non_blackout_channels" yaml:"non_blackout_channels"`
+	AllowSetUniverse    bool            `json:"allow_set_universe" yaml:"allow_set_universe"` // optional, enables SetUniverse command
+	ProtectedChannels   []int           `json:"protected_channels" yaml:"protected_channels"` // optional, universe channels never written by SetUniverse
+	ReconnectInterval   int             `json:"reconnect_interval" yaml:"reconnect_interval"`
+	FrameRate           int             `json:"frame_rate" yaml:"frame_rate"`
+	KeepAliveInterval   int             `json:"keep_alive_interval" yaml:"keep_alive_interval"`
 }
 
 // Represenation of DMX device configuration entity in user configuration
 type DMXConfig struct {
-	Alias               string        `json:"alias" yaml:"alias"`
-	Path                string        `json:"path" yaml:"path"`
-	Scenes              []SceneConfig `json:"scenes" yaml:"scenes"`
-	NonBlackoutChannels []int         `json:"non_blackout_channels" yaml:"non_blackout_channels"`
-	ReconnectInterval   int           `json:"reconnect_interval" yaml:"reconnect_interval"`
+	Alias               string          `json:"alias" yaml:"alias"`
+	Driver              string          `json:"driver" yaml:"driver"`               // "enttec_pro" (default) or "open_dmx"
+	Path                string          `json:"path" yaml:"path"`                   // port name or glob pattern, e.g. /dev/serial/by-id/usb-ENTTEC*
+	SerialNumber        string          `json:"serial_number" yaml:"serial_number"` // optional, widget is searched by serial number among matching ports
+	Fixtures            []FixtureConfig `json:"fixtures" yaml:"fixtures"`
+	Scenes              []SceneConfig   `json:"scenes" yaml:"scenes"`
+	NonBlackoutChannels []int           `json:"non_blackout_channels" yaml:"non_blackout_channels"`
+	AllowSetUniverse    bool            `json:"allow_set_universe" yaml:"allow_set_universe"` // optional, enables SetUniverse command
+	ProtectedChannels   []int           `json:"protected_channels" yaml:"protected_channels"` // optional, universe channels never written by SetUniverse
+	ReconnectInterval   int             `json:"reconnect_interval" yaml:"reconnect_interval"`
+	FrameRate           int             `json:"frame_rate" yaml:"frame_rate"`
+	Input               bool            `json:"input" yaml:"input"`               // widget receives DMX from console instead of sending universe
+	BreakTime           *int            `json:"break_time" yaml:"break_time"`     // optional, us
+	MABTime             *int            `json:"mab_time" yaml:"mab_time"`         // optional, us
+	RefreshRate         *int            `json:"refresh_rate" yaml:"refresh_rate"` // optional, packets per second, 0 is maximum
+	BaudRate            int             `json:"baud_rate" yaml:"baud_rate"`
+	ReadTimeout         int             `json:"read_timeout" yaml:"read_timeout"`   // ms
+	WriteTimeout        int             `json:"write_timeout" yaml:"write_timeout"` // ms
+	Slots               int             `json:"slots" yaml:"slots"`                 // number of transmitted channels
+}
+
+// Represenation of virtual device configuration entity in user configuration
+type VirtualConfig struct {
+	Alias               string          `json:"alias" yaml:"alias"`
+	Fixtures            []FixtureConfig `json:"fixtures" yaml:"fixtures"`
+	Scenes              []SceneConfig   `json:"scenes" yaml:"scenes"`
+	NonBlackoutChannels []int           `json:"non_blackout_channels" yaml:"non_blackout_channels"`
+	AllowSetUniverse    bool            `json:"allow_set_universe" yaml:"allow_set_universe"` // optional, enables SetUniverse command
+	ProtectedChannels   []int           `json:"protected_channels" yaml:"protected_channels"` // optional, universe channels never written by SetUniverse
+	ReconnectInterval   int             `json:"reconnect_interval" yaml:"reconnect_interval"`
+	FrameRate           int             `json:"frame_rate" yaml:"frame_rate"`
+	Dump                string          `json:"dump" yaml:"dump"`           // optional, "log" or "file"
+	DumpPath            string          `json:"dump_path" yaml:"dump_path"` // file of "file" dump
+}
+
+// Represenation of cue configuration entity
+type CueConfig struct {
+	DeviceAlias string `json:"device_alias" yaml:"device_alias"`
+	SceneAlias  string `json:"scene_alias" yaml:"scene_alias"`
+	FadeIn      int    `json:"fade_in" yaml:"fade_in"`
+	FadeOut     int    `json:"fade_out" yaml:"fade_out"`
+	Delay       int    `json:"delay" yaml:"delay"`
+	AutoFollow  int    `json:"auto_follow" yaml:"auto_follow"`
+}
+
+// Represenation of cue list configuration entity
+type CueListConfig struct {
+	Alias string      `json:"alias" yaml:"alias"`
+	Cues  []CueConfig `json:"cues" yaml:"cues"`
+}
+
+// Represenation of scene part of device group applied to single member device,
+// either configured scene of device or channel map with values is used
+type GroupScenePartConfig struct {
+	DeviceAlias string             `json:"device_alias" yaml:"device_alias"`
+	SceneAlias  string             `json:"scene_alias" yaml:"scene_alias"`
+	ChannelMap  []ChannelMapConfig `json:"channel_map" yaml:"channel_map"`
+}
+
+// Represenation of device group scene configuration entity
+type GroupSceneConfig struct {
+	Alias   string                 `json:"scene_alias" yaml:"scene_alias"`
+	Devices []GroupScenePartConfig `json:"devices" yaml:"devices"`
+}
+
+// Represenation of device group configuration entity, group is addressed by its alias as device alias of SetScene and cues
+type DeviceGroupConfig struct {
+	Alias   string             `json:"alias" yaml:"alias"`
+	Devices []string           `json:"devices" yaml:"devices"`
+	Scenes  []GroupSceneConfig `json:"scenes" yaml:"scenes"`
+}
+
+// Represenation of storage configuration entity of universes and scenes.
+// It is part of user configuration since system configuration is defined by hubman-lib and has no executor fields,
+// user configuration is also reloaded at runtime and store is reopened when storage configuration changes.
+type StorageConfig struct {
+	Type     string `json:"type" yaml:"type"` // "redis" (default), "file" or "memory"
+	Address  string `json:"address" yaml:"address"`
+	Password string `json:"password" yaml:"password"`
+	DB       int    `json:"db" yaml:"db"`
+	Prefix   string `json:"prefix" yaml:"prefix"`
+	Path     string `json:"path" yaml:"path"` // JSON file of "file" storage
+
+	FlushInterval int `json:"flush_interval" yaml:"flush_interval"` // minimal interval between universe writes, ms
 }
 
 // Represenation of user configuration entity
 type UserConfig struct {
-	DMXDevices    []DMXConfig    `json:"dmx_devices" yaml:"dmx_devices"`
-	ArtNetDevices []ArtNetConfig `json:"artnet_devices" yaml:"artnet_devices"`
+	Storage          StorageConfig          `json:"storage" yaml:"storage"`
+	ArtNet           ArtNetControllerConfig `json:"artnet" yaml:"artnet"`
+	FixtureProfiles  []FixtureProfileConfig `json:"fixture_profiles" yaml:"fixture_profiles"`
+	DMXDevices       []DMXConfig            `json:"dmx_devices" yaml:"dmx_devices"`
+	ArtNetDevices    []ArtNetConfig         `json:"artnet_devices" yaml:"artnet_devices"`
+	SACNDevices      []SACNConfig           `json:"sacn_devices" yaml:"sacn_devices"`
+	VirtualDevices   []VirtualConfig        `json:"virtual_devices" yaml:"virtual_devices"`
+	DeviceGroups     []DeviceGroupConfig    `json:"device_groups" yaml:"device_groups"`
+	CueLists         []CueListConfig        `json:"cue_lists" yaml:"cue_lists"`
+	ArtNetInputs     []ArtNetInputConfig    `json:"artnet_inputs" yaml:"artnet_inputs"`
+	SnapshotInterval int                    `json:"snapshot_interval" yaml:"snapshot_interval"` // optional, ms, UniverseSnapshot signals are sent periodically if set
 }
 
 // Function validating user configuration contents
 func (conf *UserConfig) Validate() error {
-	if len(conf.DMXDevices) == 0 && len(conf.ArtNetDevices) == 0 {
-		fmt.Println("DMX/ArtNet devices were not found in configuration file")
+	if len(conf.DMXDevices) == 0 && len(conf.ArtNetDevices) == 0 && len(conf.SACNDevices) == 0 && len(conf.VirtualDevices) == 0 {
+		fmt.Println("DMX/ArtNet/sACN/virtual devices were not found in configuration file")
 	}
 	if alias, has := conf.hasDuplicateDevices(); has {
 		return fmt.Errorf("found duplicate DMX device with alias {%s} in config", alias)
 	}
+	if conf.Storage.Type != "" && conf.Storage.Type != StorageRedis && conf.Storage.Type != StorageFile && conf.Storage.Type != StorageMemory {
+		return fmt.Errorf("valid storage type ('%s', '%s' or '%s') must be provided in config, got {%s}",
+			StorageRedis, StorageFile, StorageMemory, conf.Storage.Type)
+	}
+	if conf.Storage.Type == StorageFile && conf.Storage.Path == "" {
+		return fmt.Errorf("storage path must be provided in config for file storage")
+	}
+	if conf.SnapshotInterval < 0 {
+		return fmt.Errorf("valid snapshot interval must be provided in config, got {%d}", conf.SnapshotInterval)
+	}
+	if conf.Storage.FlushInterval < 0 {
+		return fmt.Errorf("valid storage flush interval must be provided in config, got {%d}", conf.Storage.FlushInterval)
+	}
+	if conf.Storage.DB < 0 {
+		return fmt.Errorf("valid storage Redis DB must be provided in config, got {%d}", conf.Storage.DB)
+	}
+	if conf.ArtNet.BindAddress != "" && net.ParseIP(conf.ArtNet.BindAddress) == nil {
+		return fmt.Errorf("valid ArtNet bind address must be provided in config, got {%s}", conf.ArtNet.BindAddress)
+	}
+	if conf.ArtNet.BroadcastAddress != "" && net.ParseIP(conf.ArtNet.BroadcastAddress) == nil {
+		return fmt.Errorf("valid ArtNet broadcast address must be provided in config, got {%s}", conf.ArtNet.BroadcastAddress)
+	}
+	if conf.ArtNet.InputAddress != "" || len(conf.ArtNetInputs) > 0 {
+		if _, err := net.ResolveUDPAddr("udp4", conf.ArtNet.InputAddress); conf.ArtNet.InputAddress == "" || err != nil {
+			return fmt.Errorf("valid ArtNet input address must be provided in config, got {%s}", conf.ArtNet.InputAddress)
+		}
+	}
+	err := conf.validateFixtureProfiles()
+	if err != nil {
+		return err
+	}
 	for idx, device := range conf.DMXDevices {
 		if device.Alias == "" {
 			return fmt.Errorf("device #{%d} ({%s}): "+
 				"valid DMX device_name must be provided in config",
 				idx, device.Alias)
 		}
+		if device.Driver == "" {
+			device.Driver = DMXDriverEnttecPro
+		}
+		if device.Driver != DMXDriverEnttecPro && device.Driver != DMXDriverOpenDMX {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid DMX driver ('%s' or '%s') must be provided in config",
+				idx, device.Driver, DMXDriverEnttecPro, DMXDriverOpenDMX)
+		}
+		if device.Driver == DMXDriverOpenDMX && (device.Input || device.SerialNumber != "" || device.RefreshRate != nil || device.BaudRate != 0) {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"input, serial_number, refresh_rate and baud_rate are not supported by '%s' driver",
+				idx, device.Alias, DMXDriverOpenDMX)
+		}
+		if _, err := filepath.Match(device.Path, ""); err != nil {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid DMX path or path pattern must be provided in config",
+				idx, device.Path)
+		}
+		err := validateDevicePatch(conf.FixtureProfiles, device.Fixtures, device.Scenes)
+		if err != nil {
+			return fmt.Errorf("device #{%d} ({%s}): %v", idx, device.Alias, err)
+		}
+		if device.Slots != 0 && (device.Slots < MinDMXSlots || device.Slots > DefaultDMXSlots) {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid DMX number of slots ([%d:%d]) must be provided in config",
+				idx, device.Slots, MinDMXSlots, DefaultDMXSlots)
+		}
+		if device.BaudRate < 0 || device.ReadTimeout < 0 || device.ReadTimeout > MaxDMXReadTimeout || device.WriteTimeout < 0 {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid DMX baud rate and timeouts ([0:%d] ms) must be provided in config",
+				idx, device.Alias, MaxDMXReadTimeout)
+		}
+		if device.Slots == 0 {
+			device.Slots = DefaultDMXSlots
+		}
+		maxFrameRate := MaxDMXFrameRateForSlots(device.Slots)
+		if device.FrameRate < 0 || device.FrameRate > maxFrameRate {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid DMX frame rate ([1:%d]) must be provided in config",
+				idx, device.FrameRate, maxFrameRate)
+		}
+		if device.BreakTime != nil && (*device.BreakTime < MinDMXBreakTime || *device.BreakTime > MaxDMXWidgetTime) {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid DMX break time ([%d:%d] us) must be provided in config",
+				idx, *device.BreakTime, MinDMXBreakTime, MaxDMXWidgetTime)
+		}
+		if device.MABTime != nil && (*device.MABTime < MinDMXMABTime || *device.MABTime > MaxDMXWidgetTime) {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid DMX MAB time ([%d:%d] us) must be provided in config",
+				idx, *device.MABTime, MinDMXMABTime, MaxDMXWidgetTime)
+		}
+		if device.RefreshRate != nil && (*device.RefreshRate < 0 || *device.RefreshRate > MaxDMXWidgetRefreshRate) {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid DMX widget refresh rate ([0:%d]) must be provided in config",
+				idx, *device.RefreshRate, MaxDMXWidgetRefreshRate)
+		}
 		if device.ReconnectInterval < DefaultReconnectInterval {
 			device.ReconnectInterval = DefaultReconnectInterval
-			conf.DMXDevices[idx] = device
 		}
+		if device.FrameRate == 0 {
+			device.FrameRate = DefaultFrameRate
+		}
+		conf.DMXDevices[idx] = device
 	}
 	for idx, device := range conf.ArtNetDevices {
 		if device.Alias == "" {
@@ -71,6 +331,11 @@ func (conf *UserConfig) Validate() error {
 				"valid ArtNet device_name must be provided in config",
 				idx, device.Alias)
 		}
+		if device.IP != "" && net.ParseIP(device.IP) == nil {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid ArtNet node IP must be provided in config",
+				idx, device.IP)
+		}
 		if device.Net < 0 || device.Net > 127 {
 			return fmt.Errorf("device #{%d} ({%d}): "+
 				"valid ArtNet Net address ([0:127]) must be provided in config",
@@ -81,14 +346,407 @@ func (conf *UserConfig) Validate() error {
 				"valid ArtNet SubUni address ([0:255]) must be provided in config",
 				idx, device.SubUni)
 		}
+		err := validateDevicePatch(conf.FixtureProfiles, device.Fixtures, device.Scenes)
+		if err != nil {
+			return fmt.Errorf("device #{%d} ({%s}): %v", idx, device.Alias, err)
+		}
+		if device.FrameRate < 0 {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid ArtNet frame rate must be provided in config",
+				idx, device.FrameRate)
+		}
+		if device.KeepAliveInterval < 0 {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid ArtNet keep alive interval must be provided in config",
+				idx, device.KeepAliveInterval)
+		}
 		if device.ReconnectInterval < DefaultReconnectInterval {
 			device.ReconnectInterval = DefaultReconnectInterval
-			conf.ArtNetDevices[idx] = device
+		}
+		if device.FrameRate == 0 {
+			device.FrameRate = DefaultFrameRate
+		}
+		if device.KeepAliveInterval == 0 {
+			device.KeepAliveInterval = DefaultArtNetKeepAliveInterval
+		}
+		conf.ArtNetDevices[idx] = device
+	}
+	for idx, device := range conf.SACNDevices {
+		if device.Alias == "" {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid sACN device_name must be provided in config",
+				idx, device.Alias)
+		}
+		err := validateDevicePatch(conf.FixtureProfiles, device.Fixtures, device.Scenes)
+		if err != nil {
+			return fmt.Errorf("device #{%d} ({%s}): %v", idx, device.Alias, err)
+		}
+		if device.Universe < 1 || device.Universe > 63999 {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid sACN universe ([1:63999]) must be provided in config",
+				idx, device.Universe)
+		}
+		if device.Priority < 0 || device.Priority > 200 {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid sACN priority ([0:200]) must be provided in config",
+				idx, device.Priority)
+		}
+		if len(device.SourceName) > 63 {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"sACN source name must not be longer than 63 bytes",
+				idx, device.SourceName)
+		}
+		if device.Destination != "" && net.ParseIP(device.Destination) == nil {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid sACN destination IP must be provided in config",
+				idx, device.Destination)
+		}
+		if device.Port < 0 || device.Port > 65535 {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid sACN port must be provided in config",
+				idx, device.Port)
+		}
+		if device.FrameRate < 0 || device.KeepAliveInterval < 0 {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid sACN frame rate and keep alive interval must be provided in config",
+				idx, device.Alias)
+		}
+		if device.ReconnectInterval < DefaultReconnectInterval {
+			device.ReconnectInterval = DefaultReconnectInterval
+		}
+		if device.FrameRate == 0 {
+			device.FrameRate = DefaultFrameRate
+		}
+		if device.KeepAliveInterval == 0 {
+			device.KeepAliveInterval = DefaultSACNKeepAliveInterval
+		}
+		conf.SACNDevices[idx] = device
+	}
+	for idx, device := range conf.VirtualDevices {
+		if device.Alias == "" {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid virtual device_name must be provided in config",
+				idx, device.Alias)
+		}
+		err := validateDevicePatch(conf.FixtureProfiles, device.Fixtures, device.Scenes)
+		if err != nil {
+			return fmt.Errorf("device #{%d} ({%s}): %v", idx, device.Alias, err)
+		}
+		if device.FrameRate < 0 {
+			return fmt.Errorf("device #{%d} ({%d}): "+
+				"valid virtual device frame rate must be provided in config",
+				idx, device.FrameRate)
+		}
+		if device.Dump != "" && device.Dump != VirtualDumpLog && device.Dump != VirtualDumpFile {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"valid virtual device dump ('%s' or '%s') must be provided in config",
+				idx, device.Dump, VirtualDumpLog, VirtualDumpFile)
+		}
+		if device.Dump == VirtualDumpFile && device.DumpPath == "" {
+			return fmt.Errorf("device #{%d} ({%s}): "+
+				"dump_path must be provided in config for file dump",
+				idx, device.Alias)
+		}
+		if device.ReconnectInterval < DefaultReconnectInterval {
+			device.ReconnectInterval = DefaultReconnectInterval
+		}
+		if device.FrameRate == 0 {
+			device.FrameRate = DefaultFrameRate
+		}
+		conf.VirtualDevices[idx] = device
+	}
+	err = conf.validateArtNetInputs()
+	if err != nil {
+		return err
+	}
+	err = conf.validateDeviceGroups()
+	if err != nil {
+		return err
+	}
+	return conf.validateCueLists()
+}
+
+// Function validating Artnet inputs against configured devices
+func (conf *UserConfig) validateArtNetInputs() error {
+	deviceAliases := make(map[string]struct{})
+	for _, device := range conf.DMXDevices {
+		deviceAliases[device.Alias] = struct{}{}
+	}
+	for _, device := range conf.ArtNetDevices {
+		deviceAliases[device.Alias] = struct{}{}
+	}
+	for _, device := range conf.SACNDevices {
+		deviceAliases[device.Alias] = struct{}{}
+	}
+	for _, device := range conf.VirtualDevices {
+		deviceAliases[device.Alias] = struct{}{}
+	}
+
+	for idx, input := range conf.ArtNetInputs {
+		if _, ok := deviceAliases[input.DeviceAlias]; !ok {
+			return fmt.Errorf("artnet input #{%d}: device {%s} was not found in config", idx, input.DeviceAlias)
+		}
+		if input.Net < 0 || input.Net > 127 || input.SubUni < 0 || input.SubUni > 255 {
+			return fmt.Errorf("artnet input #{%d}: "+
+				"valid ArtNet Net ([0:127]) and SubUni ([0:255]) addresses must be provided in config",
+				idx)
+		}
+		mode, err := ParseMergeMode(input.Merge)
+		if err != nil {
+			return fmt.Errorf("artnet input #{%d}: %v", idx, err)
+		}
+		if input.Timeout < 0 {
+			return fmt.Errorf("artnet input #{%d}: timeout must not be negative", idx)
+		}
+		if input.Timeout == 0 {
+			input.Timeout = DefaultInputTimeout
+		}
+		input.Merge = mode
+		conf.ArtNetInputs[idx] = input
+	}
+	return nil
+}
+
+// Function returns maximum DMX frame rate for number of slots (break, MAB and 44 us per slot including start code)
+func MaxDMXFrameRateForSlots(slots int) int {
+	frameTime := 92 + 12 + 44*(slots+1)
+	return 1000000 / frameTime
+}
+
+// Function validating fixture profiles contents
+func (conf *UserConfig) validateFixtureProfiles() error {
+	profileAliases := make(map[string]struct{})
+	for idx, profile := range conf.FixtureProfiles {
+		if profile.Alias == "" || strings.Contains(profile.Alias, ".") {
+			return fmt.Errorf("fixture profile #{%d} ({%s}): valid alias must be provided in config", idx, profile.Alias)
+		}
+		if _, has := profileAliases[profile.Alias]; has {
+			return fmt.Errorf("found duplicate fixture profile with alias {%s} in config", profile.Alias)
+		}
+		profileAliases[profile.Alias] = struct{}{}
+
+		attributeNames := make(map[string]struct{})
+		for _, attribute := range profile.Attributes {
+			if attribute.Name == "" || strings.Contains(attribute.Name, ".") {
+				return fmt.Errorf("fixture profile {%s}: valid attribute name must be provided in config", profile.Alias)
+			}
+			if _, has := attributeNames[attribute.Name]; has {
+				return fmt.Errorf("fixture profile {%s}: found duplicate attribute {%s} in config", profile.Alias, attribute.Name)
+			}
+			attributeNames[attribute.Name] = struct{}{}
+
+			if attribute.Offset < 0 || attribute.Offset > 511 {
+				return fmt.Errorf("fixture profile {%s}: attribute {%s} offset out of range [0:511]", profile.Alias, attribute.Name)
+			}
+			if attribute.FineOffset != nil && (*attribute.FineOffset < 0 || *attribute.FineOffset > 511 || *attribute.FineOffset == attribute.Offset) {
+				return fmt.Errorf("fixture profile {%s}: attribute {%s} fine offset must be in range [0:511] and differ from offset", profile.Alias, attribute.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Function validating fixture patch of device and fixture attributes referenced by its scenes
+func validateDevicePatch(profiles []FixtureProfileConfig, fixtures []FixtureConfig, scenes []SceneConfig) error {
+	patch, err := ReadPatchFromDeviceConfig(profiles, fixtures)
+	if err != nil {
+		return err
+	}
+
+	for _, scene := range scenes {
+		for _, channelMap := range scene.ChannelMap {
+			if channelMap.UniverseChannelID > 511 {
+				return fmt.Errorf("scene {%s}: universe channel {%d} out of range [0:511]", scene.Alias, channelMap.UniverseChannelID)
+			}
+			maxValue := 255
+			if channelMap.FineUniverseChannelID != nil {
+				fine := *channelMap.FineUniverseChannelID
+				if fine > 511 || fine == channelMap.UniverseChannelID {
+					return fmt.Errorf("scene {%s}: fine universe channel {%d} must be in range [0:511] and differ from universe channel", scene.Alias, fine)
+				}
+				maxValue = 65535
+			}
+			if channelMap.FixtureAttribute != "" {
+				if channel, ok := patch[channelMap.FixtureAttribute]; ok {
+					maxValue = channel.MaxValue()
+				}
+			}
+			if channelMap.Value < 0 || channelMap.Value > maxValue {
+				return fmt.Errorf("scene {%s}: channel value {%d} out of range [0:%d]", scene.Alias, channelMap.Value, maxValue)
+			}
+			if channelMap.FixtureAttribute == "" {
+				continue
+			}
+			if _, ok := patch[channelMap.FixtureAttribute]; !ok {
+				return fmt.Errorf("scene {%s}: fixture attribute {%s} is not patched", scene.Alias, channelMap.FixtureAttribute)
+			}
+		}
+	}
+	return nil
+}
+
+// Function returns configured scenes of devices by device alias
+func (conf *UserConfig) deviceScenes() map[string][]SceneConfig {
+	deviceScenes := make(map[string][]SceneConfig)
+	for _, device := range conf.DMXDevices {
+		deviceScenes[device.Alias] = device.Scenes
+	}
+	for _, device := range conf.ArtNetDevices {
+		deviceScenes[device.Alias] = device.Scenes
+	}
+	for _, device := range conf.SACNDevices {
+		deviceScenes[device.Alias] = device.Scenes
+	}
+	for _, device := range conf.VirtualDevices {
+		deviceScenes[device.Alias] = device.Scenes
+	}
+	return deviceScenes
+}
+
+// Function returns patched fixtures of devices by device alias
+func (conf *UserConfig) deviceFixtures() map[string][]FixtureConfig {
+	deviceFixtures := make(map[string][]FixtureConfig)
+	for _, device := range conf.DMXDevices {
+		deviceFixtures[device.Alias] = device.Fixtures
+	}
+	for _, device := range conf.ArtNetDevices {
+		deviceFixtures[device.Alias] = device.Fixtures
+	}
+	for _, device := range conf.SACNDevices {
+		deviceFixtures[device.Alias] = device.Fixtures
+	}
+	for _, device := range conf.VirtualDevices {
+		deviceFixtures[device.Alias] = device.Fixtures
+	}
+	return deviceFixtures
+}
+
+// Function validating device groups against configured devices and their scenes
+func (conf *UserConfig) validateDeviceGroups() error {
+	deviceScenes := conf.deviceScenes()
+	deviceFixtures := conf.deviceFixtures()
+
+	groupAliases := make(map[string]struct{})
+	for idx, group := range conf.DeviceGroups {
+		if group.Alias == "" {
+			return fmt.Errorf("device group #{%d}: valid alias must be provided in config", idx)
+		}
+		if _, has := groupAliases[group.Alias]; has {
+			return fmt.Errorf("found duplicate device group with alias {%s} in config", group.Alias)
+		}
+		if _, has := deviceScenes[group.Alias]; has {
+			return fmt.Errorf("device group {%s}: alias is already used by device in config", group.Alias)
+		}
+		groupAliases[group.Alias] = struct{}{}
+
+		if len(group.Devices) == 0 {
+			return fmt.Errorf("device group {%s}: at least one device must be provided in config", group.Alias)
+		}
+		members := make(map[string]struct{})
+		for _, deviceAlias := range group.Devices {
+			if _, ok := deviceScenes[deviceAlias]; !ok {
+				return fmt.Errorf("device group {%s}: device {%s} was not found in config", group.Alias, deviceAlias)
+			}
+			if _, has := members[deviceAlias]; has {
+				return fmt.Errorf("device group {%s}: found duplicate device {%s} in config", group.Alias, deviceAlias)
+			}
+			members[deviceAlias] = struct{}{}
+		}
+
+		sceneAliases := make(map[string]struct{})
+		for _, scene := range group.Scenes {
+			if scene.Alias == "" {
+				return fmt.Errorf("device group {%s}: valid scene_alias must be provided in config", group.Alias)
+			}
+			if _, has := sceneAliases[scene.Alias]; has {
+				return fmt.Errorf("device group {%s}: found duplicate scene {%s} in config", group.Alias, scene.Alias)
+			}
+			sceneAliases[scene.Alias] = struct{}{}
+
+			partDevices := make(map[string]struct{})
+			for _, part := range scene.Devices {
+				if _, ok := members[part.DeviceAlias]; !ok {
+					return fmt.Errorf("device group {%s} scene {%s}: device {%s} is not a member of group",
+						group.Alias, scene.Alias, part.DeviceAlias)
+				}
+				if _, has := partDevices[part.DeviceAlias]; has {
+					return fmt.Errorf("device group {%s} scene {%s}: found duplicate device {%s} in config",
+						group.Alias, scene.Alias, part.DeviceAlias)
+				}
+				partDevices[part.DeviceAlias] = struct{}{}
+
+				if (part.SceneAlias == "") == (len(part.ChannelMap) == 0) {
+					return fmt.Errorf("device group {%s} scene {%s}: either scene_alias or channel_map must be provided for device {%s}",
+						group.Alias, scene.Alias, part.DeviceAlias)
+				}
+				if part.SceneAlias != "" && !hasScene(deviceScenes[part.DeviceAlias], part.SceneAlias) {
+					return fmt.Errorf("device group {%s} scene {%s}: scene {%s} was not found for device {%s} in config",
+						group.Alias, scene.Alias, part.SceneAlias, part.DeviceAlias)
+				}
+				partScene := SceneConfig{Alias: scene.Alias, ChannelMap: part.ChannelMap}
+				err := validateDevicePatch(conf.FixtureProfiles, deviceFixtures[part.DeviceAlias], []SceneConfig{partScene})
+				if err != nil {
+					return fmt.Errorf("device group {%s} device {%s}: %v", group.Alias, part.DeviceAlias, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Function validating cue lists against configured devices, device groups and scenes
+func (conf *UserConfig) validateCueLists() error {
+	deviceScenes := conf.deviceScenes()
+	for _, group := range conf.DeviceGroups {
+		groupScenes := make([]SceneConfig, 0, len(group.Scenes))
+		for _, scene := range group.Scenes {
+			groupScenes = append(groupScenes, SceneConfig{Alias: scene.Alias})
+		}
+		deviceScenes[group.Alias] = groupScenes
+	}
+
+	cueListAliases := make(map[string]struct{})
+	for idx, cueList := range conf.CueLists {
+		if cueList.Alias == "" {
+			return fmt.Errorf("cue list #{%d}: valid alias must be provided in config", idx)
+		}
+		if _, has := cueListAliases[cueList.Alias]; has {
+			return fmt.Errorf("found duplicate cue list with alias {%s} in config", cueList.Alias)
+		}
+		cueListAliases[cueList.Alias] = struct{}{}
+
+		if len(cueList.Cues) == 0 {
+			return fmt.Errorf("cue list {%s}: at least one cue must be provided in config", cueList.Alias)
+		}
+		for cueIdx, cue := range cueList.Cues {
+			scenes, ok := deviceScenes[cue.DeviceAlias]
+			if !ok {
+				return fmt.Errorf("cue list {%s} cue #{%d}: device {%s} was not found in config",
+					cueList.Alias, cueIdx, cue.DeviceAlias)
+			}
+			if !hasScene(scenes, cue.SceneAlias) {
+				return fmt.Errorf("cue list {%s} cue #{%d}: scene {%s} was not found for device {%s} in config",
+					cueList.Alias, cueIdx, cue.SceneAlias, cue.DeviceAlias)
+			}
+			if cue.FadeIn < 0 || cue.FadeOut < 0 || cue.Delay < 0 || cue.AutoFollow < 0 {
+				return fmt.Errorf("cue list {%s} cue #{%d}: cue times must not be negative",
+					cueList.Alias, cueIdx)
+			}
 		}
 	}
 	return nil
 }
 
+// Function checks scene list containing scene with specified alias
+func hasScene(scenes []SceneConfig, sceneAlias string) bool {
+	for _, scene := range scenes {
+		if scene.Alias == sceneAlias {
+			return true
+		}
+	}
+	return false
+}
+
 // Function check duplicating aliases in device list from user configuration
 func (conf *UserConfig) hasDuplicateDevices() (string, bool) {
 	x := make(map[string]struct{})
@@ -107,6 +765,20 @@ func (conf *UserConfig) hasDuplicateDevices() (string, bool) {
 		x[v.Alias] = struct{}{}
 	}
 
+	for _, v := range conf.SACNDevices {
+		if _, has := x[v.Alias]; has {
+			return v.Alias, true
+		}
+		x[v.Alias] = struct{}{}
+	}
+
+	for _, v := range conf.VirtualDevices {
+		if _, has := x[v.Alias]; has {
+			return v.Alias, true
+		}
+		x[v.Alias] = struct{}{}
+	}
+
 	return "", false
 }
 
@@ -122,8 +794,68 @@ func ParseConfigFromBytes(data []byte) (*UserConfig, error) {
 	return &cfg, nil
 }
 
-// Function reading scene from user configuration of device
-func ReadScenesFromDeviceConfig(sceneListConfig []SceneConfig) map[string]Scene {
+// Function reading fixture patch from user configuration of device, maps "fixture.attribute" to universe channels
+func ReadPatchFromDeviceConfig(profiles []FixtureProfileConfig, fixtures []FixtureConfig) (map[string]Channel, error) {
+	profileMap := make(map[string]FixtureProfileConfig)
+	for _, profile := range profiles {
+		profileMap[profile.Alias] = profile
+	}
+
+	patch := make(map[string]Channel)
+	occupied := make(map[int]string)
+	occupy := func(key string, universeChannelID int) error {
+		if universeChannelID < 0 || universeChannelID > 511 {
+			return fmt.Errorf("attribute {%s} address out of range [0:511]", key)
+		}
+		if other, has := occupied[universeChannelID]; has {
+			return fmt.Errorf("attribute {%s} overlaps with {%s} at address {%d}", key, other, universeChannelID)
+		}
+		occupied[universeChannelID] = key
+		return nil
+	}
+
+	for _, fixture := range fixtures {
+		if fixture.Alias == "" || strings.Contains(fixture.Alias, ".") {
+			return nil, fmt.Errorf("valid fixture alias must be provided in config, got {%s}", fixture.Alias)
+		}
+		profile, ok := profileMap[fixture.Profile]
+		if !ok {
+			return nil, fmt.Errorf("fixture {%s}: profile {%s} was not found in config", fixture.Alias, fixture.Profile)
+		}
+
+		for _, attribute := range profile.Attributes {
+			key := FixtureAttributeKey(fixture.Alias, attribute.Name)
+			if _, has := patch[key]; has {
+				return nil, fmt.Errorf("found duplicate fixture with alias {%s} in config", fixture.Alias)
+			}
+
+			channel := Channel{UniverseChannelID: fixture.StartAddress + attribute.Offset}
+			err := occupy(key, channel.UniverseChannelID)
+			if err != nil {
+				return nil, fmt.Errorf("fixture {%s}: %v", fixture.Alias, err)
+			}
+			if attribute.FineOffset != nil {
+				channel.Wide = true
+				channel.FineUniverseChannelID = fixture.StartAddress + *attribute.FineOffset
+				err = occupy(key, channel.FineUniverseChannelID)
+				if err != nil {
+					return nil, fmt.Errorf("fixture {%s}: %v", fixture.Alias, err)
+				}
+			}
+			patch[key] = channel
+		}
+	}
+
+	return patch, nil
+}
+
+// Function returns patch key of fixture attribute
+func FixtureAttributeKey(fixtureAlias string, attributeName string) string {
+	return fixtureAlias + "." + attributeName
+}
+
+// Function reading scene from user configuration of device, fixture attributes are resolved with device patch
+func ReadScenesFromDeviceConfig(sceneListConfig []SceneConfig, patch map[string]Channel) map[string]Scene {
 	scenes := make(map[string]Scene)
 
 	for _, sceneConfig := range sceneListConfig {
@@ -133,7 +865,19 @@ func ReadScenesFromDeviceConfig(sceneListConfig []SceneConfig) map[string]Scene
 		for _, channelMap := range sceneConfig.ChannelMap {
 			channel := Channel{
 				UniverseChannelID: int(channelMap.UniverseChannelID),
-				Value:             0}
+				Value:             channelMap.Value}
+			if channelMap.FineUniverseChannelID != nil {
+				channel.Wide = true
+				channel.FineUniverseChannelID = int(*channelMap.FineUniverseChannelID)
+			}
+			if channelMap.FixtureAttribute != "" {
+				patchedChannel, ok := patch[channelMap.FixtureAttribute]
+				if !ok {
+					continue
+				}
+				channel = patchedChannel
+				channel.Value = channelMap.Value
+			}
 			scene.ChannelMap[int(channelMap.SceneChannelID)] = channel
 		}
 		scene.Alias = sceneConfig.Alias
@@ -143,6 +887,11 @@ func ReadScenesFromDeviceConfig(sceneListConfig []SceneConfig) map[string]Scene
 	return scenes
 }
 
+// Function reading channels protected from SetUniverse command from user configuration of device
+func ReadProtectedChannelsFromDeviceConfig(protectedChannels []int) map[int]struct{} {
+	return ReadNonBlackoutChannelsFromDeviceConfig(protectedChannels)
+}
+
 // Function reading excluded channels from blackout operations from user configuration of device
 func ReadNonBlackoutChannelsFromDeviceConfig(nonBlackoutChannels []int) map[int]struct{} {
 	nonBlackoutChannelsMap := make(map[int]struct{})
@@ -2,15 +2,43 @@ package device
 
 import (
 	"context"
-
+	"time"
 
 	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
 )
 
-// Represenation of channel entity
+// Represenation of channel entity, 16-bit (wide) channel is split across coarse (MSB) and fine (LSB) universe channels
 type Channel struct {
-	UniverseChannelID int
-	Value             int
+	UniverseChannelID     int
+	FineUniverseChannelID int
+	Wide                  bool
+	Value                 int // [0:255], [0:65535] for wide channel
+}
+
+// Function returns maximum value of channel
+func (c Channel) MaxValue() int {
+	if c.Wide {
+		return 65535
+	}
+	return 255
+}
+
+// Function reads value of channel from universe
+func (c Channel) Read(universe *[512]byte) int {
+	if c.Wide {
+		return int(universe[c.UniverseChannelID])<<8 | int(universe[c.FineUniverseChannelID])
+	}
+	return int(universe[c.UniverseChannelID])
+}
+
+// Function writes value of channel to universe
+func (c Channel) Write(universe *[512]byte, value int) {
+	if c.Wide {
+		universe[c.UniverseChannelID] = byte(value >> 8)
+		universe[c.FineUniverseChannelID] = byte(value)
+		return
+	}
+	universe[c.UniverseChannelID] = byte(value)
 }
 
 // Represenation of scene entity
@@ -22,12 +50,33 @@ type Scene struct {
 // Represenation of abstract device entity
 type Device interface {
 	GetAlias() string
+	GetBaseDevice() *BaseDevice
 	SetScene(ctx context.Context, command models.SetScene) error
 	SaveScene(ctx context.Context) error
+	LearnScene(ctx context.Context) error
+	CreateScene(ctx context.Context, command models.CreateScene) error
+	SaveSceneAs(ctx context.Context, command models.SaveSceneAs) error
+	RenameScene(ctx context.Context, command models.RenameScene) error
+	DeleteScene(ctx context.Context, command models.DeleteScene) error
 	SetChannel(ctx context.Context, command models.SetChannel) error
+	SetChannels(ctx context.Context, command models.SetChannels) error
+	SetUniverse(ctx context.Context, command models.SetUniverse) error
 	IncrementChannel(ctx context.Context, command models.IncrementChannel) error
-	WriteValueToChannel(command models.SetChannel) error
-	WriteUniverseToDevice() error
+	SetChannel16(ctx context.Context, command models.SetChannel16) error
+	IncrementChannel16(ctx context.Context, command models.IncrementChannel16) error
+	StartEffect(ctx context.Context, command models.StartEffect) error
+	StopEffect(ctx context.Context, command models.StopEffect) error
 	Blackout(ctx context.Context) error
+	GetUniverse(ctx context.Context) error
+	GetCurrentScene(ctx context.Context) error
+	ListScenes(ctx context.Context) error
+	MergeInput(source string, mode string, timeout time.Duration, data []byte)
 	Close()
+}
+
+// Represenation of device entity supporting RDM
+type RDMDevice interface {
+	DiscoverRDM(ctx context.Context, command models.RDMDiscover) error
+	SetRDMStartAddress(ctx context.Context, command models.RDMSetStartAddress) error
+	IdentifyRDM(ctx context.Context, command models.RDMIdentify) error
 }
\ No newline at end of file
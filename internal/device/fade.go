@@ -0,0 +1,176 @@
+package device
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	EasingLinear    = "linear"
+	EasingEaseInOut = "ease_in_out"
+	EasingSCurve    = "s_curve"
+)
+
+// Representation of easing curve mapping fade progress [0:1] to value progress [0:1]
+type EasingFunc func(progress float64) float64
+
+var easings = map[string]EasingFunc{
+	EasingLinear: func(progress float64) float64 {
+		return progress
+	},
+	EasingEaseInOut: func(progress float64) float64 {
+		return (1 - math.Cos(math.Pi*progress)) / 2
+	},
+	EasingSCurve: func(progress float64) float64 {
+		return progress * progress * progress * (progress*(progress*6-15) + 10)
+	},
+}
+
+// Function returns easing curve by name, empty name is treated as linear
+func ParseEasing(name string) (EasingFunc, error) {
+	if name == "" {
+		return easings[EasingLinear], nil
+	}
+
+	easing, ok := easings[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown easing '%s' (expected '%s', '%s' or '%s')", name, EasingLinear, EasingEaseInOut, EasingSCurve)
+	}
+	return easing, nil
+}
+
+// Function validates fade parameters of command and returns its easing curve
+func ParseFade(fadeMs int, easingName string) (EasingFunc, error) {
+	if fadeMs < 0 {
+		return nil, fmt.Errorf("fade time '%d' should not be negative", fadeMs)
+	}
+	return ParseEasing(easingName)
+}
+
+// Representation of fade group entity, completed when all of its channel fades are finished
+type FadeGroup struct {
+	Pending    map[int]struct{}
+	OnComplete func()
+	Cancelled  bool
+}
+
+// Representation of single channel fade entity, value range is defined by channel width
+type ChannelFade struct {
+	Channel   Channel
+	From      int
+	To        int
+	StartedAt time.Time
+	Duration  time.Duration
+	Easing    EasingFunc
+	Group     *FadeGroup
+}
+
+// Function returns interpolated value of channel fade at specified moment and flag of fade completion
+func (f *ChannelFade) ValueAt(now time.Time) (int, bool) {
+	elapsed := now.Sub(f.StartedAt)
+	if f.Duration <= 0 || elapsed >= f.Duration {
+		return f.To, true
+	}
+	if elapsed < 0 {
+		return f.From, false
+	}
+
+	progress := float64(elapsed) / float64(f.Duration)
+	if f.Easing != nil {
+		progress = f.Easing(progress)
+	}
+	return f.From + int(float64(f.To-f.From)*progress+0.5), false
+}
+
+// Representation of fade engine entity of single device
+type FadeEngine struct {
+	Fades map[int]*ChannelFade
+}
+
+// Function initializes fade engine entity
+func NewFadeEngine() *FadeEngine {
+	return &FadeEngine{
+		Fades: make(map[int]*ChannelFade),
+	}
+}
+
+// Function returns true if fade engine has running fades
+func (e *FadeEngine) Active() bool {
+	return len(e.Fades) > 0
+}
+
+// Function returns target value of running fade of single channel
+func (e *FadeEngine) Target(universeChannelID int) (int, bool) {
+	fade, ok := e.Fades[universeChannelID]
+	if !ok {
+		return 0, false
+	}
+	return fade.To, true
+}
+
+// Function adds channel fade replacing running fade of the same channel
+func (e *FadeEngine) AddFade(fade *ChannelFade) []func() {
+	completed := e.CancelChannel(fade.Channel.UniverseChannelID)
+	if fade.Group != nil {
+		fade.Group.Pending[fade.Channel.UniverseChannelID] = struct{}{}
+	}
+	e.Fades[fade.Channel.UniverseChannelID] = fade
+	return completed
+}
+
+// Function cancels running fade of single channel, returns callbacks of groups completed by cancellation
+func (e *FadeEngine) CancelChannel(universeChannelID int) []func() {
+	fade, ok := e.Fades[universeChannelID]
+	if !ok {
+		return nil
+	}
+	delete(e.Fades, universeChannelID)
+	return e.release(fade)
+}
+
+// Function cancels all running fades of group, returns callback of group if it was not completed yet
+func (e *FadeEngine) CancelGroup(group *FadeGroup) []func() {
+	if group == nil || group.Cancelled {
+		return nil
+	}
+	group.Cancelled = true
+	for universeChannelID, fade := range e.Fades {
+		if fade.Group == group {
+			delete(e.Fades, universeChannelID)
+		}
+	}
+	if len(group.Pending) == 0 || group.OnComplete == nil {
+		return nil
+	}
+	group.Pending = make(map[int]struct{})
+	return []func(){group.OnComplete}
+}
+
+// Function writes interpolated values of running fades to universe, returns callbacks of completed groups
+func (e *FadeEngine) Step(now time.Time, universe *[512]byte) []func() {
+	var completed []func()
+	for universeChannelID, fade := range e.Fades {
+		value, finished := fade.ValueAt(now)
+		fade.Channel.Write(universe, value)
+		if finished {
+			delete(e.Fades, universeChannelID)
+			completed = append(completed, e.release(fade)...)
+		}
+	}
+	return completed
+}
+
+// Function removes finished or cancelled channel fade from its group
+func (e *FadeEngine) release(fade *ChannelFade) []func() {
+	group := fade.Group
+	if group == nil || group.Cancelled {
+		return nil
+	}
+
+	delete(group.Pending, fade.Channel.UniverseChannelID)
+	if len(group.Pending) > 0 || group.OnComplete == nil {
+		return nil
+	}
+	return []func(){group.OnComplete}
+}
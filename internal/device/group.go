@@ -0,0 +1,85 @@
+package device
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Represenation of scene part of device group applied to single member device
+type ScenePart struct {
+	Device     *BaseDevice
+	SceneAlias string // configured or runtime scene of device, used if scene is not set
+	Scene      *Scene
+}
+
+// Function applies scene parts to member devices of group in the same frame with the same fade start.
+// Universes of all devices are changed while all device mutexes are held, scene of device selected by alias
+// becomes current scene of device. Callback is called once after scene is applied on every device.
+func ApplyGroupScene(parts []ScenePart, fadeMs int, fadeOutMs int, onComplete func()) error {
+	if len(parts) == 0 {
+		onComplete()
+		return nil
+	}
+
+	sorted := make([]ScenePart, len(parts))
+	copy(sorted, parts)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Device.Alias < sorted[j].Device.Alias
+	})
+
+	for _, part := range sorted {
+		if !part.Device.Connected.Load() {
+			return fmt.Errorf("no connection to device '%s'", part.Device.Alias)
+		}
+	}
+
+	for _, part := range sorted {
+		part.Device.Mutex.Lock()
+	}
+	unlock := func() {
+		for _, part := range sorted {
+			part.Device.Mutex.Unlock()
+		}
+	}
+
+	scenes := make([]Scene, 0, len(sorted))
+	for _, part := range sorted {
+		if part.Scene != nil {
+			scenes = append(scenes, *part.Scene)
+			continue
+		}
+		scene, ok := part.Device.Scenes[part.SceneAlias]
+		if !ok {
+			unlock()
+			return fmt.Errorf("invalid scene alias '%s' of device '%s'", part.SceneAlias, part.Device.Alias)
+		}
+		scenes = append(scenes, scene)
+	}
+	for idx, part := range sorted {
+		if part.Scene == nil {
+			part.Device.SelectScene(&scenes[idx])
+		}
+	}
+
+	var pending atomic.Int32
+	pending.Store(int32(len(sorted)))
+	partComplete := func() {
+		if pending.Add(-1) == 0 {
+			onComplete()
+		}
+	}
+
+	startedAt := time.Now()
+	var completed []func()
+	for idx, part := range sorted {
+		completed = append(completed, part.Device.ApplyScene(scenes[idx], fadeMs, fadeOutMs, startedAt, partComplete)...)
+	}
+	unlock()
+
+	for _, onPartComplete := range completed {
+		onPartComplete()
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package device
+
+import (
+	"context"
+	"testing"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+)
+
+// Function returns connected base device with single configured scene setting universe channel 0
+func newTestMember(t *testing.T, alias string, sceneAlias string, value int) *BaseDevice {
+	t.Helper()
+
+	persister := NewPersister(NewMemoryStore(), 0, zap.NewNop())
+	t.Cleanup(persister.Close)
+	scenes := []SceneConfig{{Alias: sceneAlias, ChannelMap: []ChannelMapConfig{{SceneChannelID: 0, UniverseChannelID: 0, Value: value}}}}
+	b := NewBaseDevice(context.Background(), alias, nil, scenes, nil, 0, 0, make(chan core.Signal, 16), zap.NewNop(), nil, persister)
+	b.Connected.Store(true)
+	return b
+}
+
+func TestApplyGroupSceneSetsCurrentScene(t *testing.T) {
+	first := newTestMember(t, "first", "warm", 100)
+	second := newTestMember(t, "second", "warm", 200)
+	explicit := Scene{Alias: "explicit", ChannelMap: map[int]Channel{0: {UniverseChannelID: 1, Value: 50}}}
+	parts := []ScenePart{
+		{Device: first, SceneAlias: "warm"},
+		{Device: second, Scene: &explicit},
+	}
+
+	completed := 0
+	err := ApplyGroupScene(parts, 0, 0, func() { completed++ })
+	if err != nil {
+		t.Fatalf("ApplyGroupScene() error = %v", err)
+	}
+
+	if completed != 1 {
+		t.Errorf("callback is called %d times, want 1", completed)
+	}
+	if first.CurrentScene == nil || first.CurrentScene.Alias != "warm" {
+		t.Errorf("current scene of member selected by alias is not set")
+	}
+	if second.CurrentScene != nil {
+		t.Errorf("current scene of member with explicit channel map is set")
+	}
+	if first.Universe[0] != 100 || second.Universe[1] != 50 {
+		t.Errorf("scene values are not written to universes")
+	}
+}
+
+func TestApplyGroupSceneCompletesSupersededScene(t *testing.T) {
+	first := newTestMember(t, "first", "warm", 100)
+	second := newTestMember(t, "second", "warm", 200)
+	parts := []ScenePart{
+		{Device: first, SceneAlias: "warm"},
+		{Device: second, SceneAlias: "warm"},
+	}
+
+	superseded := 0
+	err := ApplyGroupScene(parts, 10000, 10000, func() { superseded++ })
+	if err != nil {
+		t.Fatalf("ApplyGroupScene() error = %v", err)
+	}
+	if superseded != 0 {
+		t.Fatalf("callback of fading scene is called before fade is completed")
+	}
+
+	completed := 0
+	err = ApplyGroupScene(parts, 0, 0, func() { completed++ })
+	if err != nil {
+		t.Fatalf("ApplyGroupScene() error = %v", err)
+	}
+	if superseded != 1 {
+		t.Errorf("callback of superseded scene is called %d times, want 1", superseded)
+	}
+	if completed != 1 {
+		t.Errorf("callback is called %d times, want 1", completed)
+	}
+}
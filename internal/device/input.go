@@ -0,0 +1,101 @@
+package device
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	MergeModeLTP  = "ltp"
+	MergeModeHTP  = "htp"
+	MergeModeNone = "none" // input is only recorded, e.g. for learning scenes
+
+	DefaultInputTimeout = 10000
+)
+
+// Function validates merge mode of external input, empty mode is treated as HTP
+func ParseMergeMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return MergeModeHTP, nil
+	case MergeModeLTP, MergeModeHTP, MergeModeNone:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown merge mode '%s' (expected '%s', '%s' or '%s')", mode, MergeModeLTP, MergeModeHTP, MergeModeNone)
+}
+
+// Representation of external input layer entity of single device
+type InputLayer struct {
+	Mode       string
+	Frame      [512]byte
+	Length     int
+	Received   bool
+	ReceivedAt time.Time
+	Timeout    time.Duration
+}
+
+// Function initializes external input layer entity
+func NewInputLayer(mode string, timeout time.Duration) *InputLayer {
+	return &InputLayer{
+		Mode:    mode,
+		Timeout: timeout,
+	}
+}
+
+// Function returns true if input layer has data not older than its timeout
+func (l *InputLayer) Live(now time.Time) bool {
+	return l.Received && (l.Timeout <= 0 || now.Sub(l.ReceivedAt) < l.Timeout)
+}
+
+// Function stores received frame in input layer
+func (l *InputLayer) Update(now time.Time, data []byte) {
+	l.Length = copy(l.Frame[:], data)
+	l.Received = true
+	l.ReceivedAt = now
+}
+
+// Function merges highest values of HTP input layer into output frame
+func (l *InputLayer) Apply(now time.Time, frame *[512]byte) {
+	if l.Mode != MergeModeHTP || !l.Live(now) {
+		return
+	}
+	for i := 0; i < l.Length; i++ {
+		if l.Frame[i] > frame[i] {
+			frame[i] = l.Frame[i]
+		}
+	}
+}
+
+// Representation of external input layers of single device by source, e.g. Artnet port-address or DMX widget
+type InputLayers map[string]*InputLayer
+
+// Function returns input layer of source, settings of existing layer are updated without dropping its frame
+func (l InputLayers) Get(source string, mode string, timeout time.Duration) *InputLayer {
+	layer, ok := l[source]
+	if !ok {
+		layer = NewInputLayer(mode, timeout)
+		l[source] = layer
+		return layer
+	}
+	layer.Mode = mode
+	layer.Timeout = timeout
+	return layer
+}
+
+// Function merges highest values of live HTP input layers into output frame
+func (l InputLayers) Apply(now time.Time, frame *[512]byte) {
+	for _, layer := range l {
+		layer.Apply(now, frame)
+	}
+}
+
+// Function returns most recently received live input layer, nil if no input is live
+func (l InputLayers) Latest(now time.Time) *InputLayer {
+	var latest *InputLayer
+	for _, layer := range l {
+		if layer.Live(now) && (latest == nil || layer.ReceivedAt.After(latest.ReceivedAt)) {
+			latest = layer
+		}
+	}
+	return latest
+}
@@ -0,0 +1,149 @@
+package device
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+)
+
+// Function returns base device without output loop, stored in memory
+func newTestDevice(t *testing.T) *BaseDevice {
+	t.Helper()
+
+	persister := NewPersister(NewMemoryStore(), 0, zap.NewNop())
+	t.Cleanup(persister.Close)
+	return NewBaseDevice(context.Background(), "test", nil, nil, nil, 0, 0, make(chan core.Signal, 16), zap.NewNop(), nil, persister)
+}
+
+// Function returns output frame of device at specified moment as built by output loop
+func outputFrame(b *BaseDevice, now time.Time) [512]byte {
+	frame := b.Universe
+	b.Inputs.Apply(now, &frame)
+	return frame
+}
+
+func TestMergeInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		universe     []byte
+		input        []byte
+		wantUniverse []byte
+		wantOutput   []byte
+	}{
+		{
+			name:         "ltp overwrites universe",
+			mode:         MergeModeLTP,
+			universe:     []byte{100, 100, 100},
+			input:        []byte{50, 200, 0},
+			wantUniverse: []byte{50, 200, 0},
+			wantOutput:   []byte{50, 200, 0},
+		},
+		{
+			name:         "htp merges highest values into output",
+			mode:         MergeModeHTP,
+			universe:     []byte{100, 100, 100},
+			input:        []byte{50, 200, 0},
+			wantUniverse: []byte{100, 100, 100},
+			wantOutput:   []byte{100, 200, 100},
+		},
+		{
+			name:         "none only records input",
+			mode:         MergeModeNone,
+			universe:     []byte{100, 100, 100},
+			input:        []byte{50, 200, 0},
+			wantUniverse: []byte{100, 100, 100},
+			wantOutput:   []byte{100, 100, 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestDevice(t)
+			copy(b.Universe[:], tt.universe)
+
+			b.MergeInput("test", tt.mode, time.Second, tt.input)
+
+			output := outputFrame(b, time.Now())
+			for i := range tt.wantUniverse {
+				if b.Universe[i] != tt.wantUniverse[i] {
+					t.Errorf("universe[%d] = %d, want %d", i, b.Universe[i], tt.wantUniverse[i])
+				}
+				if output[i] != tt.wantOutput[i] {
+					t.Errorf("output[%d] = %d, want %d", i, output[i], tt.wantOutput[i])
+				}
+			}
+			if b.Inputs.Latest(time.Now()) == nil {
+				t.Errorf("input frame is not recorded")
+			}
+		})
+	}
+}
+
+func TestMergeInputLTPKeepsLocalChanges(t *testing.T) {
+	b := newTestDevice(t)
+
+	b.MergeInput("test", MergeModeLTP, time.Second, []byte{10, 20})
+	b.Universe[0] = 99 // local command after input
+	b.MergeInput("test", MergeModeLTP, time.Second, []byte{10, 30})
+
+	if b.Universe[0] != 99 {
+		t.Errorf("unchanged input channel overwrote local value, universe[0] = %d", b.Universe[0])
+	}
+	if b.Universe[1] != 30 {
+		t.Errorf("changed input channel was not applied, universe[1] = %d", b.Universe[1])
+	}
+}
+
+func TestMergeInputHTPExpires(t *testing.T) {
+	b := newTestDevice(t)
+
+	b.MergeInput("test", MergeModeHTP, 100*time.Millisecond, []byte{200})
+	if output := outputFrame(b, time.Now()); output[0] != 200 {
+		t.Fatalf("output[0] = %d, want 200", output[0])
+	}
+	if output := outputFrame(b, time.Now().Add(time.Second)); output[0] != 0 {
+		t.Errorf("expired input is merged, output[0] = %d", output[0])
+	}
+}
+
+func TestMergeInputKeepsLayerPerSource(t *testing.T) {
+	b := newTestDevice(t)
+
+	b.MergeInput("artnet 0:1", MergeModeHTP, time.Second, []byte{200, 0})
+	b.MergeInput("artnet 0:2", MergeModeHTP, 100*time.Millisecond, []byte{0, 150})
+	b.MergeInput("artnet 0:1", MergeModeHTP, time.Second, []byte{200, 0})
+
+	output := outputFrame(b, time.Now())
+	if output[0] != 200 || output[1] != 150 {
+		t.Errorf("output = %v, want both sources merged", output[:2])
+	}
+	output = outputFrame(b, time.Now().Add(500*time.Millisecond))
+	if output[0] != 200 || output[1] != 0 {
+		t.Errorf("output = %v, want only live source merged", output[:2])
+	}
+}
+
+func TestMergeInputLatestSource(t *testing.T) {
+	b := newTestDevice(t)
+
+	b.MergeInput("dmx", MergeModeNone, 100*time.Millisecond, []byte{10})
+	b.MergeInput("artnet 0:1", MergeModeNone, time.Second, []byte{20})
+	if input := b.Inputs.Latest(time.Now()); input == nil || input.Frame[0] != 20 {
+		t.Fatalf("latest input is not the last received source")
+	}
+
+	b.MergeInput("dmx", MergeModeNone, 100*time.Millisecond, []byte{30})
+	if input := b.Inputs.Latest(time.Now()); input == nil || input.Frame[0] != 30 {
+		t.Errorf("latest input is not the last received source")
+	}
+	if input := b.Inputs.Latest(time.Now().Add(500 * time.Millisecond)); input == nil || input.Frame[0] != 20 {
+		t.Errorf("expired source is returned as latest input")
+	}
+	if input := b.Inputs.Latest(time.Now().Add(10 * time.Second)); input != nil {
+		t.Errorf("input is live after all sources expired")
+	}
+}
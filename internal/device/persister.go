@@ -0,0 +1,106 @@
+package device
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Representation of write-behind persister entity, universes of dirty devices are written to store
+// at most once per flush interval outside of command path
+type Persister struct {
+	Store    Store
+	interval time.Duration
+	dirty    map[*BaseDevice]struct{}
+	mutex    sync.Mutex
+	logger   *zap.Logger
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Function initializes persister entity and starts its flush loop
+func NewPersister(store Store, flushInterval int, logger *zap.Logger) *Persister {
+	if flushInterval <= 0 {
+		flushInterval = DefaultFlushInterval
+	}
+
+	persister := &Persister{
+		Store:    store,
+		interval: time.Duration(flushInterval) * time.Millisecond,
+		dirty:    make(map[*BaseDevice]struct{}),
+		mutex:    sync.Mutex{},
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go persister.run()
+	return persister
+}
+
+// Function marks universe or current scene of device as changed, state is written on next flush
+func (p *Persister) MarkDirty(b *BaseDevice) {
+	p.mutex.Lock()
+	p.dirty[b] = struct{}{}
+	p.mutex.Unlock()
+}
+
+// Function writes universe of device immediately if it has unsaved changes
+func (p *Persister) Flush(b *BaseDevice) {
+	p.mutex.Lock()
+	_, ok := p.dirty[b]
+	delete(p.dirty, b)
+	p.mutex.Unlock()
+
+	if ok {
+		p.write(b)
+	}
+}
+
+// Function writes universes of all devices with unsaved changes
+func (p *Persister) FlushAll() {
+	p.mutex.Lock()
+	devices := p.dirty
+	p.dirty = make(map[*BaseDevice]struct{})
+	p.mutex.Unlock()
+
+	for b := range devices {
+		p.write(b)
+	}
+}
+
+// Function writes universe and current scene alias of single device to store
+func (p *Persister) write(b *BaseDevice) {
+	err := b.WriteUniverse(context.Background())
+	if err != nil {
+		p.logger.Warn("save universe to cache failed", zap.Error(err), zap.Any("device", b.Alias))
+	}
+	err = b.WriteCurrentScene(context.Background())
+	if err != nil {
+		p.logger.Warn("save current scene to cache failed", zap.Error(err), zap.Any("device", b.Alias))
+	}
+}
+
+// Function flushes dirty universes with configured interval until persister is closed
+func (p *Persister) run() {
+	ticker := time.NewTicker(p.interval)
+	defer close(p.done)
+
+	for {
+		select {
+		case <-p.stop:
+			ticker.Stop()
+			return
+		case <-ticker.C:
+			p.FlushAll()
+		}
+	}
+}
+
+// Function stops flush loop and writes remaining changes
+func (p *Persister) Close() {
+	close(p.stop)
+	<-p.done
+	p.FlushAll()
+}
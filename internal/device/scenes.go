@@ -0,0 +1,189 @@
+package device
+
+import (
+	"context"
+	"fmt"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+	"go.uber.org/zap"
+)
+
+// Function creates scene of single device from channel map, fixture attributes are resolved with device patch
+func (b *BaseDevice) CreateScene(ctx context.Context, command models.CreateScene) error {
+	if command.SceneAlias == "" {
+		return fmt.Errorf("scene alias must be provided")
+	}
+	if len(command.ChannelMap) == 0 {
+		return fmt.Errorf("no channels specified for scene")
+	}
+
+	scene := Scene{Alias: command.SceneAlias, ChannelMap: make(map[int]Channel)}
+	for _, sceneChannel := range command.ChannelMap {
+		if sceneChannel.SceneChannelID < 0 || sceneChannel.SceneChannelID > 511 {
+			return fmt.Errorf("scene channel '%d' out of range [0:511]", sceneChannel.SceneChannelID)
+		}
+		if _, ok := scene.ChannelMap[sceneChannel.SceneChannelID]; ok {
+			return fmt.Errorf("duplicated scene channel '%d'", sceneChannel.SceneChannelID)
+		}
+
+		channel := Channel{UniverseChannelID: sceneChannel.UniverseChannelID}
+		if sceneChannel.FineUniverseChannelID != nil {
+			channel.Wide = true
+			channel.FineUniverseChannelID = *sceneChannel.FineUniverseChannelID
+		}
+		if sceneChannel.Attribute != "" {
+			patchedChannel, ok := b.Patch[sceneChannel.Attribute]
+			if !ok {
+				return fmt.Errorf("fixture attribute '%s' is not patched", sceneChannel.Attribute)
+			}
+			channel = patchedChannel
+		}
+
+		if channel.UniverseChannelID < 0 || channel.UniverseChannelID > 511 {
+			return fmt.Errorf("universe channel '%d' out of range [0:511]", channel.UniverseChannelID)
+		}
+		if channel.Wide && (channel.FineUniverseChannelID < 0 || channel.FineUniverseChannelID > 511) {
+			return fmt.Errorf("fine universe channel '%d' out of range [0:511]", channel.FineUniverseChannelID)
+		}
+		if sceneChannel.Value < 0 || sceneChannel.Value > channel.MaxValue() {
+			return fmt.Errorf("channel value '%d' out of range [0, %d]", sceneChannel.Value, channel.MaxValue())
+		}
+		channel.Value = sceneChannel.Value
+		scene.ChannelMap[sceneChannel.SceneChannelID] = channel
+	}
+
+	b.Mutex.Lock()
+	if _, ok := b.Scenes[scene.Alias]; ok {
+		b.Mutex.Unlock()
+		return fmt.Errorf("scene '%s' already exists", scene.Alias)
+	}
+	b.addScene(scene)
+	b.Mutex.Unlock()
+
+	b.SaveSceneToCache(ctx, scene, "")
+	b.SendSignal(models.SceneCreated{DeviceAlias: b.Alias, SceneAlias: scene.Alias})
+	return nil
+}
+
+// Function saves channels of current scene with current values as new scene of single device and selects it
+func (b *BaseDevice) SaveSceneAs(ctx context.Context, command models.SaveSceneAs) error {
+	if command.SceneAlias == "" {
+		return fmt.Errorf("scene alias must be provided")
+	}
+
+	b.Mutex.Lock()
+	if b.CurrentScene == nil {
+		b.Mutex.Unlock()
+		return fmt.Errorf("no scene is selected")
+	}
+	if _, ok := b.Scenes[command.SceneAlias]; ok {
+		b.Mutex.Unlock()
+		return fmt.Errorf("scene '%s' already exists", command.SceneAlias)
+	}
+
+	scene := Scene{Alias: command.SceneAlias, ChannelMap: make(map[int]Channel)}
+	for sceneChannelID, channel := range b.CurrentScene.ChannelMap {
+		channel.Value = channel.Read(&b.Universe)
+		scene.ChannelMap[sceneChannelID] = channel
+	}
+	b.addScene(scene)
+	b.CurrentScene = &scene
+	b.Mutex.Unlock()
+
+	b.SaveSceneToCache(ctx, scene, "")
+	b.SaveUniverseToCache(ctx)
+	b.SendSignal(models.SceneCreated{DeviceAlias: b.Alias, SceneAlias: scene.Alias})
+	b.CreateSceneChangedSignal(scene.Alias)
+	return nil
+}
+
+// Function renames scene of single device, current scene keeps being selected under new alias
+func (b *BaseDevice) RenameScene(ctx context.Context, command models.RenameScene) error {
+	if command.NewSceneAlias == "" {
+		return fmt.Errorf("new scene alias must be provided")
+	}
+
+	b.Mutex.Lock()
+	scene, ok := b.Scenes[command.SceneAlias]
+	if !ok {
+		b.Mutex.Unlock()
+		return fmt.Errorf("invalid scene alias '%s'", command.SceneAlias)
+	}
+	if _, ok := b.Scenes[command.NewSceneAlias]; ok {
+		b.Mutex.Unlock()
+		return fmt.Errorf("scene '%s' already exists", command.NewSceneAlias)
+	}
+
+	b.removeScene(scene.Alias)
+	scene.Alias = command.NewSceneAlias
+	b.addScene(scene)
+	current := b.CurrentScene != nil && b.CurrentScene.Alias == command.SceneAlias
+	if current {
+		b.CurrentScene = &scene
+	}
+	b.Mutex.Unlock()
+
+	b.SaveSceneToCache(ctx, scene, command.SceneAlias)
+	if current {
+		b.SaveUniverseToCache(ctx)
+	}
+	b.SendSignal(models.SceneRenamed{DeviceAlias: b.Alias, SceneAlias: command.SceneAlias, NewSceneAlias: command.NewSceneAlias})
+	return nil
+}
+
+// Function deletes scene of single device, current scene can't be deleted
+func (b *BaseDevice) DeleteScene(ctx context.Context, command models.DeleteScene) error {
+	b.Mutex.Lock()
+	if _, ok := b.Scenes[command.SceneAlias]; !ok {
+		b.Mutex.Unlock()
+		return fmt.Errorf("invalid scene alias '%s'", command.SceneAlias)
+	}
+	if b.CurrentScene != nil && b.CurrentScene.Alias == command.SceneAlias {
+		b.Mutex.Unlock()
+		return fmt.Errorf("current scene '%s' can't be deleted", command.SceneAlias)
+	}
+	b.removeScene(command.SceneAlias)
+	b.Mutex.Unlock()
+
+	b.SaveSceneToCache(ctx, Scene{}, command.SceneAlias)
+	b.SendSignal(models.SceneDeleted{DeviceAlias: b.Alias, SceneAlias: command.SceneAlias})
+	return nil
+}
+
+// Function adds scene created at runtime, configured scene re-created under its alias is no longer removed,
+// caller must hold device mutex
+func (b *BaseDevice) addScene(scene Scene) {
+	b.Scenes[scene.Alias] = scene
+	b.CreatedScenes[scene.Alias] = struct{}{}
+	delete(b.RemovedScenes, scene.Alias)
+}
+
+// Function removes scene, configured scene is remembered as removed, caller must hold device mutex
+func (b *BaseDevice) removeScene(sceneAlias string) {
+	delete(b.Scenes, sceneAlias)
+	delete(b.CreatedScenes, sceneAlias)
+	if _, ok := b.ConfiguredScenes[sceneAlias]; ok {
+		b.RemovedScenes[sceneAlias] = struct{}{}
+	}
+}
+
+// Function saves scene created at runtime and scene index to cache, scene with previous alias is deleted if specified
+func (b *BaseDevice) SaveSceneToCache(ctx context.Context, scene Scene, previousAlias string) {
+	if scene.Alias != "" {
+		err := b.WriteScene(ctx, scene)
+		if err != nil {
+			b.Logger.Warn(fmt.Sprintf("writing scene '%s' to cache failed", scene.Alias), zap.Error(err))
+		}
+	}
+	if previousAlias != "" {
+		err := b.DeleteCachedScene(ctx, previousAlias)
+		if err != nil {
+			b.Logger.Warn(fmt.Sprintf("deleting scene '%s' from cache failed", previousAlias), zap.Error(err))
+		}
+	}
+
+	err := b.WriteSceneIndex(ctx)
+	if err != nil {
+		b.Logger.Warn("writing scene index to cache failed", zap.Error(err))
+	}
+}
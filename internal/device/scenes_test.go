@@ -0,0 +1,46 @@
+package device
+
+import (
+	"context"
+	"testing"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+)
+
+func TestRenameConfiguredSceneBack(t *testing.T) {
+	ctx := context.Background()
+	persister := NewPersister(NewMemoryStore(), 0, zap.NewNop())
+	t.Cleanup(persister.Close)
+	scenes := []SceneConfig{{Alias: "scene", ChannelMap: []ChannelMapConfig{{SceneChannelID: 0, UniverseChannelID: 10}}}}
+	b := NewBaseDevice(ctx, "test", nil, scenes, nil, 0, 0, make(chan core.Signal, 16), zap.NewNop(), nil, persister)
+
+	if err := b.RenameScene(ctx, models.RenameScene{SceneAlias: "scene", NewSceneAlias: "renamed"}); err != nil {
+		t.Fatalf("RenameScene() error = %v", err)
+	}
+	if _, ok := b.RemovedScenes["scene"]; !ok {
+		t.Errorf("renamed configured scene is not removed")
+	}
+
+	if err := b.RenameScene(ctx, models.RenameScene{SceneAlias: "renamed", NewSceneAlias: "scene"}); err != nil {
+		t.Fatalf("RenameScene() error = %v", err)
+	}
+	if _, ok := b.RemovedScenes["scene"]; ok {
+		t.Errorf("re-created configured scene is still removed")
+	}
+	if _, ok := b.CreatedScenes["renamed"]; ok {
+		t.Errorf("renamed away scene is still created")
+	}
+
+	if err := b.DeleteScene(ctx, models.DeleteScene{SceneAlias: "scene"}); err != nil {
+		t.Fatalf("DeleteScene() error = %v", err)
+	}
+	if _, ok := b.RemovedScenes["scene"]; !ok {
+		t.Errorf("deleted configured scene is not removed")
+	}
+	if _, ok := b.CreatedScenes["scene"]; ok {
+		t.Errorf("deleted scene is still created")
+	}
+}
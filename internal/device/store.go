@@ -0,0 +1,228 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	StorageRedis  = "redis"
+	StorageFile   = "file"
+	StorageMemory = "memory"
+
+	DefaultRedisAddress = "localhost:6379"
+)
+
+// Error returned by store for missing key
+var ErrNotFound = errors.New("key not found")
+
+// Representation of persistence backend of device universes and scenes
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string) error
+	Delete(ctx context.Context, key string) error
+	Close() error
+}
+
+// Function initializes store from user configuration, Redis store is used by default
+func NewStore(conf StorageConfig) (Store, error) {
+	switch conf.Type {
+	case "", StorageRedis:
+		return NewRedisStore(conf), nil
+	case StorageFile:
+		return NewFileStore(conf.Path)
+	case StorageMemory:
+		return NewMemoryStore(), nil
+	}
+	return nil, fmt.Errorf("unknown storage type '%s'", conf.Type)
+}
+
+// Representation of Redis store entity, keys are prefixed with configured prefix
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// Function initializes Redis store entity with single client
+func NewRedisStore(conf StorageConfig) *RedisStore {
+	address := conf.Address
+	if address == "" {
+		address = DefaultRedisAddress
+	}
+
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:     address,
+			Password: conf.Password,
+			DB:       conf.DB,
+		}),
+		prefix: conf.Prefix,
+	}
+}
+
+// Function reads value by key from Redis
+func (s *RedisStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Result()
+	if err == redis.Nil {
+		return "", ErrNotFound
+	}
+	return value, err
+}
+
+// Function writes value by key to Redis
+func (s *RedisStore) Set(ctx context.Context, key string, value string) error {
+	return s.client.Set(ctx, s.prefix+key, value, 0).Err()
+}
+
+// Function deletes key from Redis
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
+
+// Function closes Redis client
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// Representation of in-memory store entity
+type MemoryStore struct {
+	values map[string]string
+	mutex  sync.Mutex
+}
+
+// Function initializes in-memory store entity
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		values: make(map[string]string),
+	}
+}
+
+// Function reads value by key from memory
+func (s *MemoryStore) Get(_ context.Context, key string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, ok := s.values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Function writes value by key to memory
+func (s *MemoryStore) Set(_ context.Context, key string, value string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.values[key] = value
+	return nil
+}
+
+// Function deletes key from memory
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.values, key)
+	return nil
+}
+
+// Function frees resources of in-memory store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// Representation of JSON file store entity, whole file is rewritten on every write
+type FileStore struct {
+	path   string
+	values map[string]string
+	mutex  sync.Mutex
+}
+
+// Function initializes JSON file store entity, missing file is treated as empty store
+func NewFileStore(path string) (*FileStore, error) {
+	store := &FileStore{
+		path:   path,
+		values: make(map[string]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading store file '%s' failed: %v", path, err)
+	}
+	err = json.Unmarshal(data, &store.values)
+	if err != nil {
+		return nil, fmt.Errorf("decoding store file '%s' failed: %v", path, err)
+	}
+	return store, nil
+}
+
+// Function reads value by key from file store
+func (s *FileStore) Get(_ context.Context, key string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, ok := s.values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Function writes value by key and saves file atomically
+func (s *FileStore) Set(_ context.Context, key string, value string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.values[key] = value
+	return s.save()
+}
+
+// Function deletes key and saves file atomically
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.values[key]; !ok {
+		return nil
+	}
+	delete(s.values, key)
+	return s.save()
+}
+
+// Function writes all values to temporary file and replaces store file with it, caller must hold store mutex
+func (s *FileStore) save() error {
+	data, err := json.MarshalIndent(s.values, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Function frees resources of file store
+func (s *FileStore) Close() error {
+	return nil
+}
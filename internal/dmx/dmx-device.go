@@ -3,6 +3,8 @@ package dmx
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"git.miem.hse.ru/hubman/hubman-lib/core"
@@ -12,22 +14,65 @@ import (
 	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
 )
 
+// Source of input layer of DMX widget in input mode
+const InputSource = "dmx"
+
 // Function initializes and returns DMX device entity
-func NewDMXDevice(ctx context.Context, signals chan core.Signal, conf device.DMXConfig, logger *zap.Logger, checkManager core.CheckRegistry) (device.Device, error) {
+func NewDMXDevice(ctx context.Context, signals chan core.Signal, conf device.DMXConfig, profiles []device.FixtureProfileConfig, logger *zap.Logger, checkManager core.CheckRegistry, persister *device.Persister) (device.Device, error) {
+	patch, err := device.ReadPatchFromDeviceConfig(profiles, conf.Fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	serialConf := DefaultConfig()
+	if conf.BaudRate > 0 {
+		serialConf.Baud = conf.BaudRate
+	}
+	if conf.ReadTimeout > 0 {
+		serialConf.ReadTimeout = time.Duration(conf.ReadTimeout) * time.Millisecond
+	}
+	if conf.WriteTimeout > 0 {
+		serialConf.WriteTimeout = time.Duration(conf.WriteTimeout) * time.Millisecond
+	}
+	if conf.Slots > 0 {
+		serialConf.Slots = conf.Slots
+	}
+	if conf.BreakTime != nil {
+		serialConf.BreakTime = time.Duration(*conf.BreakTime) * time.Microsecond
+	}
+	if conf.MABTime != nil {
+		serialConf.MABTime = time.Duration(*conf.MABTime) * time.Microsecond
+	}
+
 	newDMX := &dmxDevice{
-		BaseDevice: *device.NewBaseDevice(ctx, conf.Alias, conf.NonBlackoutChannels, conf.Scenes, conf.ReconnectInterval, signals, logger, checkManager),
+		BaseDevice: *device.NewBaseDevice(ctx, conf.Alias, conf.NonBlackoutChannels, conf.Scenes, patch, conf.ReconnectInterval, conf.FrameRate, signals, logger, checkManager, persister),
+		driver:     conf.Driver,
 		path:       conf.Path,
+		serial:     conf.SerialNumber,
+		serialConf: serialConf,
+		input:      conf.Input,
+		params:     conf,
 		dev:        nil,
 	}
 
+	newDMX.AllowSetUniverse = conf.AllowSetUniverse
+	newDMX.ProtectedChannels = device.ReadProtectedChannelsFromDeviceConfig(conf.ProtectedChannels)
+
 	go newDMX.reconnect()
+	go newDMX.RunOutput(newDMX.WriteFrameToDevice)
 	return newDMX, nil
 }
 
 type dmxDevice struct {
 	device.BaseDevice
-	path string
-	dev  *DMX
+	driver     string
+	path       string // port name or glob pattern
+	serial     string // widget serial number
+	serialConf Config
+	input      bool // widget receives DMX instead of sending universe
+	params     device.DMXConfig
+	dev        Driver
+	ioMutex    sync.Mutex // guards dev, device mutex is not held during serial I/O
 }
 
 // Function reconnects single DMX device
@@ -48,9 +93,67 @@ func (d *dmxDevice) reconnect() {
 	}
 }
 
+// Function checks connection of single DMX device by resending last frame, failed write marks device as disconnected.
+// Device in input mode is checked by its reading loop.
+func (d *dmxDevice) checkHealth() {
+	if d.input {
+		return
+	}
+
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
+
+	if !d.Connected.Load() {
+		return
+	}
+	err := d.dev.Render()
+	if err != nil {
+		d.dev.Close()
+		d.Connected.CompareAndSwap(true, false)
+		d.Logger.Warn("DMX device health check failed", zap.Error(err))
+	}
+}
+
+// Function scans serial ports matching device path and opens widget with configured serial number
+func (d *dmxDevice) open() (Driver, string, error) {
+	path := d.path
+	if path == "" && d.serial == "" {
+		path = DEV
+	}
+
+	err := fmt.Errorf("no serial port matches '%s'", path)
+	for _, port := range MatchSerialPorts(path) {
+		if d.driver == device.DMXDriverOpenDMX {
+			dev, openErr := NewOpenDMXConnection(port, d.serialConf)
+			if openErr != nil {
+				err = openErr
+				continue
+			}
+			return dev, port, nil
+		}
+
+		dev, openErr := NewDMXConnection(port, d.serialConf)
+		if openErr != nil {
+			err = openErr
+			continue
+		}
+		if d.serial == "" {
+			return dev, port, nil
+		}
+
+		serialNumber, serialErr := dev.GetSerialNumber()
+		if serialErr == nil && strings.TrimLeft(serialNumber, "0") == strings.TrimLeft(d.serial, "0") {
+			return dev, port, nil
+		}
+		dev.Close()
+		err = fmt.Errorf("widget with serial number '%s' was not found", d.serial)
+	}
+	return nil, "", err
+}
+
 // Function connects to the DMX device through OS
 func (d *dmxDevice) connect() {
-	dev, err := NewDMXConnection(d.path)
+	dev, port, err := d.open()
 	if err != nil {
 		connCheck := core.NewCheck(
 			fmt.Sprintf(device.DeviceDisconnectedCheckLabelFormat, d.Alias),
@@ -61,129 +164,116 @@ func (d *dmxDevice) connect() {
 		return
 	}
 
-	d.Connected.CompareAndSwap(false, true)
+	widget, isWidget := dev.(*DMX)
+	if isWidget {
+		d.configureWidget(widget)
+	}
 
-	d.Mutex.Lock()
+	if d.input && isWidget {
+		err = widget.SetReceiveMode(false)
+		if err != nil {
+			dev.Close()
+			d.Logger.Warn("Unable to switch DMX device to input mode", zap.Any("port", port), zap.Error(err))
+			return
+		}
+	}
+
+	d.ioMutex.Lock()
 	d.dev = dev
-	d.Mutex.Unlock()
-	d.WriteUniverseToDevice()
+	d.ioMutex.Unlock()
+	d.Connected.CompareAndSwap(false, true)
+
+	if d.input && isWidget {
+		go d.readInput(widget)
+	}
 
 	connCheck := core.NewCheck(
 		fmt.Sprintf(device.DeviceDisconnectedCheckLabelFormat, d.Alias),
 		"",
 	)
 	d.CheckManager.RegisterSuccess(connCheck)
-	d.Logger.Info("Connected DMX device", zap.Any("path", d.path))
-}
-
-// Function checks availability of single DMX device
-func (d *dmxDevice) checkHealth() {
-	d.Mutex.Lock()
-	defer d.Mutex.Unlock()
-
-	err := d.dev.Render()
-	if err != nil {
-		d.dev.Close()
-		d.Connected.Store(false)
-	}
+	d.Logger.Info("Connected DMX device", zap.Any("path", d.path), zap.Any("port", port))
 }
 
-// Function writes universe to single DMX device
-func (d *dmxDevice) WriteUniverseToDevice() error {
-	err := d.BaseDevice.WriteUniverseToDevice()
+// Function writes configured timing parameters to widget and reports widget info, widgets without replies are skipped
+func (d *dmxDevice) configureWidget(dev *DMX) {
+	params, err := dev.GetWidgetParameters()
 	if err != nil {
-		return err
+		d.Logger.Warn("Unable to read DMX widget parameters", zap.Any("port", dev.dev), zap.Error(err))
+		return
 	}
 
-	d.Mutex.Lock()
-	defer d.Mutex.Unlock()
-
-	for i := 0; i < 512; i++ {
-		err := d.dev.SetChannel(i, d.Universe[i])
+	if d.params.BreakTime != nil || d.params.MABTime != nil || d.params.RefreshRate != nil {
+		if d.params.BreakTime != nil {
+			params.BreakTime = MicrosecondsToWidgetTime(*d.params.BreakTime)
+		}
+		if d.params.MABTime != nil {
+			params.MABTime = MicrosecondsToWidgetTime(*d.params.MABTime)
+		}
+		if d.params.RefreshRate != nil {
+			params.RefreshRate = byte(*d.params.RefreshRate)
+		}
+		err = dev.SetWidgetParameters(params)
 		if err != nil {
-			return fmt.Errorf("setting value to channel error: %v", err)
+			d.Logger.Warn("Unable to set DMX widget parameters", zap.Any("port", dev.dev), zap.Error(err))
 		}
 	}
 
-	err = d.dev.Render()
-	if err != nil {
-		d.dev.Close()
-		d.Connected.CompareAndSwap(true, false)
-		return fmt.Errorf("sending frame to device error: %v", err)
-	}
-	return nil
-}
-
-// Function sets scene specified in command and updates universe of single DMX device
-func (d *dmxDevice) SetScene(ctx context.Context, command models.SetScene) error {
-	err := d.BaseDevice.SetScene(ctx, command)
+	serialNumber, err := dev.GetSerialNumber()
 	if err != nil {
-		return err
+		d.Logger.Warn("Unable to read DMX widget serial number", zap.Any("port", dev.dev), zap.Error(err))
 	}
 
-	for _, channel := range d.CurrentScene.ChannelMap {
-		d.Universe[channel.UniverseChannelID] = byte(channel.Value)
-		d.WriteValueToChannel(
-			models.SetChannel{
-				Channel:     channel.UniverseChannelID,
-				Value:       channel.Value,
-				DeviceAlias: d.Alias})
-	}
-	d.SaveUniverseToCache(ctx)
-	d.CreateSceneChangedSignal()
-	return nil
+	d.SendSignal(models.DeviceInfo{
+		DeviceAlias:     d.Alias,
+		SerialNumber:    serialNumber,
+		FirmwareVersion: fmt.Sprintf("%d.%d", params.FirmwareVersion>>8, params.FirmwareVersion&0xFF),
+		BreakTime:       WidgetTimeToMicroseconds(params.BreakTime),
+		MABTime:         WidgetTimeToMicroseconds(params.MABTime),
+		RefreshRate:     int(params.RefreshRate),
+	})
 }
 
-// Function sets and writes value to channel of universe specified in command for single DMX device
-func (d *dmxDevice) SetChannel(ctx context.Context, command models.SetChannel) error {
-	err := d.BaseDevice.SetChannel(ctx, &command)
-	if err != nil {
-		return err
-	}
-
-	err = d.WriteValueToChannel(command)
-	if err != nil {
-		return err
+// Function reads frames received by DMX device in input mode, failed read marks device as disconnected
+func (d *dmxDevice) readInput(dev *DMX) {
+	for {
+		frame, err := dev.ReadFrame()
+		if IsTimeout(err) {
+			continue
+		}
+		if err != nil {
+			d.ioMutex.Lock()
+			if d.dev == dev && d.Connected.CompareAndSwap(true, false) {
+				dev.Close()
+				d.Logger.Warn("reading DMX input failed", zap.Error(err))
+			}
+			d.ioMutex.Unlock()
+			return
+		}
+		d.MergeInput(InputSource, device.MergeModeNone, time.Duration(device.DefaultInputTimeout)*time.Millisecond, frame)
 	}
-	return nil
 }
 
-// Function writes incremented value to channel of universe specified in command for single DMX device
-func (d *dmxDevice) IncrementChannel(ctx context.Context, command models.IncrementChannel) error {
-	err := d.BaseDevice.IncrementChannel(ctx, &command)
-	if err != nil {
-		return err
-	}
-
-	cmd := models.SetChannel{
-		Channel:     command.Channel,
-		Value:       int(d.Universe[command.Channel]),
-		DeviceAlias: d.Alias}
+// Function writes frame to single DMX device, failed write marks device as disconnected
+func (d *dmxDevice) WriteFrameToDevice(frame [512]byte) error {
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
 
-	err = d.WriteValueToChannel(cmd)
-	if err != nil {
-		return err
+	if !d.Connected.Load() {
+		return fmt.Errorf("no connection to device")
 	}
-
-	return nil
-}
-
-// Function writes value to channel of universe specified in command for single DMX device
-func (d *dmxDevice) WriteValueToChannel(command models.SetChannel) error {
-	err := d.BaseDevice.WriteValueToChannel(command)
-	if err != nil {
-		return err
+	if d.input {
+		return nil
 	}
 
-	err = d.dev.SetChannel(command.Channel, byte(command.Value))
-	if err != nil {
-		return err
+	for i := 0; i < 512; i++ {
+		err := d.dev.SetChannel(i, frame[i])
+		if err != nil {
+			return fmt.Errorf("setting value to channel error: %v", err)
+		}
 	}
 
-	d.Mutex.Lock()
-	defer d.Mutex.Unlock()
-
-	err = d.dev.Render()
+	err := d.dev.Render()
 	if err != nil {
 		d.dev.Close()
 		d.Connected.CompareAndSwap(true, false)
@@ -192,25 +282,12 @@ func (d *dmxDevice) WriteValueToChannel(command models.SetChannel) error {
 	return nil
 }
 
-// Function handles blackout for whole DMX universe of single DMX device
-func (d *dmxDevice) Blackout(ctx context.Context) error {
-	err := d.BaseDevice.Blackout(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = d.WriteUniverseToDevice()
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // Function frees resources of DMX device entity
-func (d *dmxDevice) Close(){
+func (d *dmxDevice) Close() {
 	d.BaseDevice.Close()
-	if d.Connected.Load() {
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
+	if d.Connected.CompareAndSwap(true, false) {
 		d.dev.Close()
 	}
-}
\ No newline at end of file
+}
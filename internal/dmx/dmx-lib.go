@@ -10,39 +10,89 @@
 package dmx
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
+	"time"
 
 	serial "github.com/tarm/goserial"
 )
 
 const (
-	START_VAL       = 0x7E
-	END_VAL         = 0xE7
-	BAUD            = 57600
-	TIMEOUT         = 1
-	DEV             = "/dev/ttyUSB0"
-	FRAME_SIZE      = 512
-	FRAME_SIZE_LOW  = byte(FRAME_SIZE & 0xFF)
-	FRAME_SIZE_HIGH = byte(FRAME_SIZE >> 8 & 0xFF)
+	START_VAL      = 0x7E
+	END_VAL        = 0xE7
+	BAUD           = 57600
+	TIMEOUT        = 1
+	WRITE_TIMEOUT  = 1
+	DEV            = "/dev/ttyUSB0"
+	FRAME_SIZE     = 512
+	MIN_FRAME_SIZE = 24
 )
 
+// Serial connection parameters.
+type Config struct {
+	Baud         int
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration // 0 disables write timeout
+	Slots        int           // number of slots sent by Render, [24:512]
+	BreakTime    time.Duration // generated by host, used by Open DMX driver only
+	MABTime      time.Duration // generated by host, used by Open DMX driver only
+}
+
+// Default serial connection parameters of Enttec DMX USB Pro.
+func DefaultConfig() Config {
+	return Config{
+		Baud:         BAUD,
+		ReadTimeout:  TIMEOUT * time.Second,
+		WriteTimeout: WRITE_TIMEOUT * time.Second,
+		Slots:        FRAME_SIZE,
+	}
+}
+
 var labels = map[string]byte{
-	"GET_WIDGET_PARAMETERS": 3, // unused
-	"SET_WIDGET_PARAMETERS": 4, // unused
-	"RX_DMX_PACKET":         5, // unused
-	"TX_DMX_PACKET":         6,
-	"TX_RDM_PACKET_REQUEST": 7, // unused
-	"RX_DMX_ON_CHANGE":      8, // unused
+	"GET_WIDGET_PARAMETERS":    3,
+	"SET_WIDGET_PARAMETERS":    4,
+	"RX_DMX_PACKET":            5,
+	"TX_DMX_PACKET":            6,
+	"TX_RDM_PACKET_REQUEST":    7,
+	"RX_DMX_ON_CHANGE":         8,
+	"RX_DMX_CHANGE_OF_STATE":   9,
+	"GET_WIDGET_SERIAL_NUMBER": 10,
+	"TX_RDM_DISCOVERY_REQUEST": 11,
+	"RX_RDM_TIMEOUT":           12,
+}
+
+const (
+	RX_STATUS_QUEUE_OVERFLOW = 0x01
+	RX_STATUS_OVERRUN        = 0x02
+	RX_CHANGE_BLOCK_SIZE     = 8
+	RX_CHANGE_BITS_SIZE      = 5
+	MAX_MESSAGE_SIZE         = 600
+	REPLY_ATTEMPTS           = 64    // messages skipped while waiting for reply
+	WIDGET_TIME_UNIT         = 10.67 // microseconds, unit of break and MAB time
+)
+
+// Widget parameters of Enttec DMX USB Pro.
+type WidgetParameters struct {
+	FirmwareVersion uint16
+	BreakTime       byte // in 10.67 us units, [9:127]
+	MABTime         byte // in 10.67 us units, [1:127]
+	RefreshRate     byte // packets per second, [0:40], 0 is maximum
 }
 
 // A serial DMX connection.
 type DMX struct {
 	dev            string
+	claimed        string // resolved port released on close
 	frame          [FRAME_SIZE]byte
 	packet         [FRAME_SIZE + 10]byte
 	serial         io.ReadWriteCloser
+	conf           Config
+	reader         *bufio.Reader
+	received       [FRAME_SIZE + 1]byte // start code and slots received in input mode
 	redChan        int
 	blueChan       int
 	greenChan      int
@@ -50,8 +100,11 @@ type DMX struct {
 }
 
 // Creates a new DMX connection using a serial device.
-func NewDMXConnection(device string) (dmx *DMX, err error) {
-	dmx = &DMX{}
+func NewDMXConnection(device string, conf Config) (dmx *DMX, err error) {
+	if conf.Slots < MIN_FRAME_SIZE || conf.Slots > FRAME_SIZE {
+		return nil, fmt.Errorf("invalid number of slots [%d]", conf.Slots)
+	}
+	dmx = &DMX{conf: conf}
 
 	// Set serial device or use default.
 	dmx.dev = device
@@ -59,11 +112,23 @@ func NewDMXConnection(device string) (dmx *DMX, err error) {
 		dmx.dev = DEV
 	}
 
-	c := &serial.Config{Name: dmx.dev, Baud: BAUD}
+	claimed, ok := claimPort(dmx.dev)
+	if !ok {
+		return nil, fmt.Errorf("port [%s] is already opened", dmx.dev)
+	}
+
+	c := &serial.Config{Name: dmx.dev, Baud: conf.Baud, ReadTimeout: conf.ReadTimeout}
 	dmx.serial, err = serial.OpenPort(c)
 	if err != nil {
+		releasePort(claimed)
 		return
 	}
+	if dmx.serial == nil {
+		releasePort(claimed)
+		return nil, fmt.Errorf("unsupported baud rate [%d]", conf.Baud)
+	}
+	dmx.claimed = claimed
+	dmx.reader = bufio.NewReader(&timeoutReader{r: dmx.serial, timeout: conf.ReadTimeout, dev: dmx.dev})
 	log.Printf("Opened port [%s].", dmx.dev)
 	return
 }
@@ -86,7 +151,7 @@ func (dmx *DMX) ClearChannel(channel int) error {
 	if err != nil {
 		return err
 	}
-	
+
 	dmx.frame[channel] = 0
 	return nil
 }
@@ -103,22 +168,312 @@ func (dmx *DMX) Render() error {
 	p := dmx.packet[:0]
 	p = append(p, START_VAL)
 	p = append(p, labels["TX_DMX_PACKET"])
-	p = append(p, FRAME_SIZE_LOW)
-	p = append(p, FRAME_SIZE_HIGH)
+	p = append(p, byte((dmx.conf.Slots+1)&0xFF))
+	p = append(p, byte((dmx.conf.Slots+1)>>8&0xFF))
 	p = append(p, 0)
-	p = append(p, dmx.frame[0:]...)
+	p = append(p, dmx.frame[:dmx.conf.Slots]...)
 	p = append(p, END_VAL)
 
 	// Write dmx frame.
-	_, err := dmx.serial.Write(p)
-	if err != nil {
+	return writeWithTimeout(dmx.serial, p, dmx.conf.WriteTimeout, dmx.dev)
+}
+
+// Write bytes to serial device, fails if write is not finished within write timeout.
+// Serial device should be closed after timeout to release pending write.
+func writeWithTimeout(w io.Writer, p []byte, timeout time.Duration, dev string) error {
+	if timeout <= 0 {
+		_, err := w.Write(p)
 		return err
 	}
+
+	// pending write keeps its own copy, caller may reuse buffer after timeout
+	data := make([]byte, len(p))
+	copy(data, p)
+	result := make(chan error, 1)
+	go func() {
+		_, err := w.Write(data)
+		result <- err
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("write to port [%s] timed out", dev)
+	}
+}
+
+// Send widget message with label and data to serial device.
+func (dmx *DMX) WriteMessage(label byte, data []byte) error {
+	p := make([]byte, 0, len(data)+5)
+	p = append(p, START_VAL)
+	p = append(p, label)
+	p = append(p, byte(len(data)&0xFF))
+	p = append(p, byte(len(data)>>8&0xFF))
+	p = append(p, data...)
+	p = append(p, END_VAL)
+
+	return writeWithTimeout(dmx.serial, p, dmx.conf.WriteTimeout, dmx.dev)
+}
+
+// Read next widget message from serial device, bytes before start delimiter are skipped.
+func (dmx *DMX) ReadMessage() (label byte, data []byte, err error) {
+	for {
+		var b byte
+		b, err = dmx.reader.ReadByte()
+		if err != nil {
+			return
+		}
+		if b != START_VAL {
+			continue
+		}
+
+		header := make([]byte, 3)
+		_, err = io.ReadFull(dmx.reader, header)
+		if err != nil {
+			return
+		}
+		size := int(header[1]) | int(header[2])<<8
+		if size > MAX_MESSAGE_SIZE {
+			continue
+		}
+
+		message := make([]byte, size+1)
+		_, err = io.ReadFull(dmx.reader, message)
+		if err != nil {
+			return
+		}
+		if message[size] != END_VAL {
+			continue
+		}
+		return header[0], message[:size], nil
+	}
+}
+
+// Convert microseconds to widget time units.
+func MicrosecondsToWidgetTime(us int) byte {
+	return byte(math.Round(float64(us) / WIDGET_TIME_UNIT))
+}
+
+// Convert widget time units to microseconds.
+func WidgetTimeToMicroseconds(units byte) int {
+	return int(math.Round(float64(units) * WIDGET_TIME_UNIT))
+}
+
+// Error of serial read finished by read timeout without data.
+var ErrReadTimeout = errors.New("serial read timed out")
+
+// Serial reader distinguishing read timeout from disconnected device.
+// Serial driver reports both as empty read, read timeout is reported only
+// if empty read took at least half of timeout (timeout resolution is 0.1s).
+type timeoutReader struct {
+	r       io.Reader
+	timeout time.Duration
+	dev     string
+}
+
+func (t *timeoutReader) Read(p []byte) (int, error) {
+	started := time.Now()
+	n, err := t.r.Read(p)
+	if n > 0 || (err != nil && !errors.Is(err, io.EOF)) {
+		return n, err
+	}
+
+	timeout := t.timeout
+	if timeout < 100*time.Millisecond {
+		timeout = 100 * time.Millisecond
+	}
+	if t.timeout > 0 && time.Since(started) >= timeout/2 {
+		return 0, ErrReadTimeout
+	}
+	return 0, fmt.Errorf("port [%s] is disconnected: %w", t.dev, io.ErrUnexpectedEOF)
+}
+
+// Check read error caused by serial read timeout.
+func IsTimeout(err error) bool {
+	return errors.Is(err, ErrReadTimeout)
+}
+
+// Send request to widget and wait for reply with the same label.
+func (dmx *DMX) request(label byte, data []byte) ([]byte, error) {
+	err := dmx.WriteMessage(label, data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < REPLY_ATTEMPTS; i++ {
+		replyLabel, reply, err := dmx.ReadMessage()
+		if err != nil {
+			if IsTimeout(err) {
+				return nil, fmt.Errorf("no reply from widget to request [%d]", label)
+			}
+			return nil, err
+		}
+		if replyLabel == label {
+			return reply, nil
+		}
+	}
+	return nil, fmt.Errorf("no reply from widget to request [%d]", label)
+}
+
+// Read firmware version, break time, MAB time and refresh rate of widget.
+func (dmx *DMX) GetWidgetParameters() (WidgetParameters, error) {
+	reply, err := dmx.request(labels["GET_WIDGET_PARAMETERS"], []byte{0, 0})
+	if err != nil {
+		return WidgetParameters{}, err
+	}
+	if len(reply) < 5 {
+		return WidgetParameters{}, fmt.Errorf("widget parameters reply is too short")
+	}
+
+	return WidgetParameters{
+		FirmwareVersion: uint16(reply[0]) | uint16(reply[1])<<8,
+		BreakTime:       reply[2],
+		MABTime:         reply[3],
+		RefreshRate:     reply[4],
+	}, nil
+}
+
+// Write break time, MAB time and refresh rate of widget, firmware version is ignored.
+func (dmx *DMX) SetWidgetParameters(params WidgetParameters) error {
+	return dmx.WriteMessage(labels["SET_WIDGET_PARAMETERS"], []byte{0, 0, params.BreakTime, params.MABTime, params.RefreshRate})
+}
+
+// Read serial number of widget.
+func (dmx *DMX) GetSerialNumber() (string, error) {
+	reply, err := dmx.request(labels["GET_WIDGET_SERIAL_NUMBER"], nil)
+	if err != nil {
+		return "", err
+	}
+	if len(reply) < 4 {
+		return "", fmt.Errorf("widget serial number reply is too short")
+	}
+
+	// serial number is BCD encoded, least significant byte first
+	return fmt.Sprintf("%02x%02x%02x%02x", reply[3], reply[2], reply[1], reply[0]), nil
+}
+
+// Send RDM request (starting with start code) and wait for response.
+// Nil response is returned if no responder answered.
+func (dmx *DMX) SendRDM(packet []byte) ([]byte, error) {
+	return dmx.rdmRequest(labels["TX_RDM_PACKET_REQUEST"], packet)
+}
+
+// Send RDM broadcast request, responders don't reply to broadcasts so no reply is awaited.
+func (dmx *DMX) BroadcastRDM(packet []byte) error {
+	return dmx.WriteMessage(labels["TX_RDM_PACKET_REQUEST"], packet)
+}
+
+// Send RDM discovery unique branch request and wait for raw discovery response.
+// Nil response is returned if no responder answered, collisions are returned as is.
+func (dmx *DMX) SendRDMDiscovery(packet []byte) ([]byte, error) {
+	return dmx.rdmRequest(labels["TX_RDM_DISCOVERY_REQUEST"], packet)
+}
+
+// Send RDM request and wait for received packet or RDM timeout message.
+func (dmx *DMX) rdmRequest(label byte, packet []byte) ([]byte, error) {
+	err := dmx.WriteMessage(label, packet)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < REPLY_ATTEMPTS; i++ {
+		replyLabel, reply, err := dmx.ReadMessage()
+		if err != nil {
+			if IsTimeout(err) {
+				return nil, fmt.Errorf("no reply from widget to RDM request [%d]", label)
+			}
+			return nil, err
+		}
+
+		switch replyLabel {
+		case labels["RX_RDM_TIMEOUT"]:
+			return nil, nil
+		case labels["RX_DMX_PACKET"]:
+			// status byte and received data
+			if len(reply) < 2 {
+				return nil, nil
+			}
+			return reply[1:], nil
+		}
+	}
+	return nil, fmt.Errorf("no reply from widget to RDM request [%d]", label)
+}
+
+// Ask widget to send received DMX always or only on change.
+func (dmx *DMX) SetReceiveMode(onChangeOnly bool) error {
+	mode := byte(0)
+	if onChangeOnly {
+		mode = 1
+	}
+	return dmx.WriteMessage(labels["RX_DMX_ON_CHANGE"], []byte{mode})
+}
+
+// Read next DMX frame received by widget in input mode.
+// Both full received packets and change of state packets are handled.
+func (dmx *DMX) ReadFrame() ([]byte, error) {
+	for {
+		label, data, err := dmx.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		switch label {
+		case labels["RX_DMX_PACKET"]:
+			// status byte, start code and slots
+			if len(data) < 2 || data[0]&(RX_STATUS_QUEUE_OVERFLOW|RX_STATUS_OVERRUN) != 0 || data[1] != 0 {
+				continue
+			}
+			n := copy(dmx.received[:], data[1:])
+			for i := n; i < len(dmx.received); i++ {
+				dmx.received[i] = 0
+			}
+		case labels["RX_DMX_CHANGE_OF_STATE"]:
+			err := dmx.applyChangeOfState(data)
+			if err != nil {
+				continue
+			}
+		default:
+			continue
+		}
+
+		frame := make([]byte, FRAME_SIZE)
+		copy(frame, dmx.received[1:])
+		return frame, nil
+	}
+}
+
+// Apply change of state packet (block number, change bit array, changed bytes) to received frame.
+func (dmx *DMX) applyChangeOfState(data []byte) error {
+	if len(data) < 1+RX_CHANGE_BITS_SIZE {
+		return fmt.Errorf("change of state packet is too short")
+	}
+
+	start := int(data[0]) * RX_CHANGE_BLOCK_SIZE
+	values := data[1+RX_CHANGE_BITS_SIZE:]
+	k := 0
+	for i := 0; i < RX_CHANGE_BITS_SIZE*8; i++ {
+		if data[1+i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		if k >= len(values) {
+			return fmt.Errorf("change of state packet is truncated")
+		}
+		if start+i < len(dmx.received) {
+			dmx.received[start+i] = values[k]
+		}
+		k++
+	}
 	return nil
 }
 
 // Close serial port.
 func (dmx *DMX) Close() error {
+	if dmx.claimed != "" {
+		releasePort(dmx.claimed)
+	}
 	return dmx.serial.Close()
 }
 
@@ -0,0 +1,92 @@
+package dmx
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	MAX_WINDOWS_PORT = 64
+)
+
+var (
+	openPorts      = make(map[string]struct{})
+	openPortsMutex sync.Mutex
+)
+
+// Resolve serial port name, symlinks (e.g. /dev/serial/by-id/...) are resolved to tty device.
+func resolvePort(name string) string {
+	resolved, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return name
+	}
+	return resolved
+}
+
+// Mark serial port as opened, returns resolved port to be released on close
+// and false if port is already opened by another connection.
+func claimPort(name string) (string, bool) {
+	openPortsMutex.Lock()
+	defer openPortsMutex.Unlock()
+
+	port := resolvePort(name)
+	if _, ok := openPorts[port]; ok {
+		return port, false
+	}
+	openPorts[port] = struct{}{}
+	return port, true
+}
+
+// Mark resolved serial port returned by claimPort as closed, symlink of unplugged widget can't be resolved anymore.
+func releasePort(port string) {
+	openPortsMutex.Lock()
+	defer openPortsMutex.Unlock()
+
+	delete(openPorts, port)
+}
+
+// Check port name being glob pattern (e.g. /dev/serial/by-id/usb-ENTTEC*).
+func IsPortPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// List serial ports of USB widgets available in system.
+func ListSerialPorts() []string {
+	var patterns []string
+	switch runtime.GOOS {
+	case "windows":
+		ports := make([]string, 0, MAX_WINDOWS_PORT)
+		for i := 1; i <= MAX_WINDOWS_PORT; i++ {
+			ports = append(ports, fmt.Sprintf("COM%d", i))
+		}
+		return ports
+	case "darwin":
+		patterns = []string{"/dev/cu.usbserial*"}
+	default:
+		patterns = []string{"/dev/ttyUSB*", "/dev/ttyACM*"}
+	}
+
+	var ports []string
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		ports = append(ports, matches...)
+	}
+	return ports
+}
+
+// List serial ports matching port name, name may be glob pattern.
+// All serial ports are listed if name is empty.
+func MatchSerialPorts(name string) []string {
+	if name == "" {
+		return ListSerialPorts()
+	}
+	if !IsPortPattern(name) {
+		return []string{name}
+	}
+
+	ports, _ := filepath.Glob(name)
+	return ports
+}
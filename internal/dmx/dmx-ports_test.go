@@ -0,0 +1,39 @@
+package dmx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReleasePortAfterSymlinkIsRemoved(t *testing.T) {
+	dir := t.TempDir()
+	tty := filepath.Join(dir, "ttyUSB0")
+	link := filepath.Join(dir, "usb-ENTTEC_DMX_USB_PRO")
+	if err := os.WriteFile(tty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(tty, link); err != nil {
+		t.Skipf("symlinks are not available: %v", err)
+	}
+
+	claimed, ok := claimPort(link)
+	if !ok {
+		t.Fatalf("claimPort(%q) failed", link)
+	}
+	if _, ok := claimPort(tty); ok {
+		t.Fatalf("port is claimed twice through symlink and tty device")
+	}
+
+	// widget is unplugged, its by-id symlink disappears
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	releasePort(claimed)
+
+	claimed, ok = claimPort(tty)
+	if !ok {
+		t.Fatalf("port is not released after symlink is removed")
+	}
+	releasePort(claimed)
+}
@@ -0,0 +1,141 @@
+package dmx
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/rdm"
+)
+
+// Representation of RDM transport through connected widget of DMX device, requests are serialized with frame output
+type rdmTransport struct {
+	device *dmxDevice
+	widget *DMX
+}
+
+// Function sends RDM request through widget
+func (t *rdmTransport) SendRDM(packet []byte) ([]byte, error) {
+	t.device.ioMutex.Lock()
+	defer t.device.ioMutex.Unlock()
+
+	if !t.device.Connected.Load() || t.device.dev != t.widget {
+		return nil, fmt.Errorf("no connection to device")
+	}
+	return t.widget.SendRDM(packet)
+}
+
+// Function sends RDM broadcast request through widget without waiting for reply
+func (t *rdmTransport) BroadcastRDM(packet []byte) error {
+	t.device.ioMutex.Lock()
+	defer t.device.ioMutex.Unlock()
+
+	if !t.device.Connected.Load() || t.device.dev != t.widget {
+		return fmt.Errorf("no connection to device")
+	}
+	return t.widget.BroadcastRDM(packet)
+}
+
+// Function sends RDM discovery request through widget
+func (t *rdmTransport) SendRDMDiscovery(packet []byte) ([]byte, error) {
+	t.device.ioMutex.Lock()
+	defer t.device.ioMutex.Unlock()
+
+	if !t.device.Connected.Load() || t.device.dev != t.widget {
+		return nil, fmt.Errorf("no connection to device")
+	}
+	return t.widget.SendRDMDiscovery(packet)
+}
+
+// Function returns RDM controller of connected widget of DMX device
+func (d *dmxDevice) rdmController() (*rdm.Controller, error) {
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
+
+	if !d.Connected.Load() {
+		return nil, fmt.Errorf("no connection to device")
+	}
+	if d.input {
+		return nil, fmt.Errorf("RDM is not available in input mode")
+	}
+	widget, ok := d.dev.(*DMX)
+	if !ok {
+		return nil, fmt.Errorf("RDM is not supported by '%s' driver", d.driver)
+	}
+	return rdm.NewController(&rdmTransport{device: d, widget: widget}), nil
+}
+
+// Function runs RDM discovery on single DMX device and sends discovered responders as signals
+func (d *dmxDevice) DiscoverRDM(ctx context.Context, command models.RDMDiscover) error {
+	controller, err := d.rdmController()
+	if err != nil {
+		return err
+	}
+
+	uids, err := controller.Discover()
+	if err != nil {
+		return err
+	}
+	for _, uid := range uids {
+		responder, err := controller.Describe(uid)
+		if err != nil {
+			d.Logger.Warn("reading RDM responder info failed", zap.String("uid", uid.String()), zap.Error(err))
+			continue
+		}
+		d.CreateRDMResponderSignal(responder)
+	}
+	return nil
+}
+
+// Function sets DMX start address of RDM responder and sends its updated info as signal
+func (d *dmxDevice) SetRDMStartAddress(ctx context.Context, command models.RDMSetStartAddress) error {
+	uid, err := rdm.ParseUID(command.UID)
+	if err != nil {
+		return err
+	}
+	controller, err := d.rdmController()
+	if err != nil {
+		return err
+	}
+
+	err = controller.SetStartAddress(uid, command.Address)
+	if err != nil {
+		return err
+	}
+
+	responder, err := controller.Describe(uid)
+	if err != nil {
+		d.Logger.Warn("reading RDM responder info failed", zap.String("uid", uid.String()), zap.Error(err))
+		return nil
+	}
+	d.CreateRDMResponderSignal(responder)
+	return nil
+}
+
+// Function switches identify mode of RDM responder
+func (d *dmxDevice) IdentifyRDM(ctx context.Context, command models.RDMIdentify) error {
+	uid, err := rdm.ParseUID(command.UID)
+	if err != nil {
+		return err
+	}
+	controller, err := d.rdmController()
+	if err != nil {
+		return err
+	}
+
+	return controller.Identify(uid, command.Identify)
+}
+
+// Function creates RDM responder signal
+func (d *dmxDevice) CreateRDMResponderSignal(responder rdm.Responder) {
+	d.SendSignal(models.RDMResponder{
+		DeviceAlias:  d.Alias,
+		UID:          responder.UID.String(),
+		Manufacturer: responder.Manufacturer,
+		Model:        responder.Model,
+		StartAddress: responder.StartAddress,
+		Footprint:    responder.Footprint,
+	})
+}
@@ -0,0 +1,185 @@
+//go:build 386 || amd64 || arm || arm64
+
+package dmx
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/rdm"
+)
+
+// Representation of Enttec DMX USB Pro widget emulated on master side of pseudo-terminal, with single RDM responder on its line
+type widgetEmulator struct {
+	t            *testing.T
+	line         *os.File
+	uid          rdm.UID
+	muted        bool
+	startAddress int
+	identify     bool
+	mutex        sync.Mutex
+}
+
+// Function reads widget messages from line and answers them until line is closed
+func (w *widgetEmulator) run() {
+	header := make([]byte, 4)
+	for {
+		_, err := io.ReadFull(w.line, header)
+		if err != nil {
+			return
+		}
+		if header[0] != START_VAL {
+			w.t.Errorf("widget message starts with 0x%02X", header[0])
+			return
+		}
+		message := make([]byte, int(header[2])|int(header[3])<<8+1)
+		_, err = io.ReadFull(w.line, message)
+		if err != nil {
+			return
+		}
+		if message[len(message)-1] != END_VAL {
+			w.t.Errorf("widget message [%d] ends with 0x%02X", header[1], message[len(message)-1])
+			return
+		}
+
+		label, reply := w.handle(header[1], message[:len(message)-1])
+		if label == 0 {
+			continue
+		}
+		frame := append([]byte{START_VAL, label, byte(len(reply)), byte(len(reply) >> 8)}, reply...)
+		_, err = w.line.Write(append(frame, END_VAL))
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Function handles widget message and returns label and data of reply, zero label if widget doesn't reply
+func (w *widgetEmulator) handle(label byte, data []byte) (byte, []byte) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	timeout := labels["RX_RDM_TIMEOUT"]
+	request := rdm.Message{}
+	if label == labels["TX_RDM_PACKET_REQUEST"] || label == labels["TX_RDM_DISCOVERY_REQUEST"] {
+		err := request.UnmarshalBinary(data)
+		if err != nil {
+			w.t.Errorf("widget received invalid RDM request: %v", err)
+			return timeout, nil
+		}
+	}
+
+	switch {
+	case label == labels["TX_RDM_DISCOVERY_REQUEST"]:
+		var lower, upper rdm.UID
+		copy(lower[:], request.ParameterData[:6])
+		copy(upper[:], request.ParameterData[6:])
+		if w.muted || w.uid.Uint64() < lower.Uint64() || w.uid.Uint64() > upper.Uint64() {
+			return timeout, nil
+		}
+		return labels["RX_DMX_PACKET"], append([]byte{0}, rdm.EncodeDiscoveryResponse(w.uid)...)
+	case label == labels["TX_RDM_PACKET_REQUEST"] && request.Destination == rdm.BroadcastUID:
+		if request.PID == rdm.PIDDiscUnMute {
+			w.muted = false
+		}
+		return 0, nil
+	case label == labels["TX_RDM_PACKET_REQUEST"] && request.Destination == w.uid:
+		response := rdm.Message{
+			Destination:  request.Source,
+			Source:       w.uid,
+			Transaction:  request.Transaction,
+			PortID:       rdm.ResponseAck,
+			CommandClass: request.CommandClass + 1,
+			PID:          request.PID,
+		}
+		switch {
+		case request.CommandClass == rdm.CommandDiscovery && request.PID == rdm.PIDDiscMute:
+			w.muted = true
+			response.ParameterData = []byte{0, 0}
+		case request.CommandClass == rdm.CommandGet && request.PID == rdm.PIDDeviceInfo:
+			info := make([]byte, 19)
+			binary.BigEndian.PutUint16(info[2:4], 0x0102)
+			binary.BigEndian.PutUint16(info[10:12], 3)
+			binary.BigEndian.PutUint16(info[14:16], uint16(w.startAddress))
+			response.ParameterData = info
+		case request.CommandClass == rdm.CommandGet && request.PID == rdm.PIDManufacturerLabel:
+			response.ParameterData = []byte("Acme")
+		case request.CommandClass == rdm.CommandSet && request.PID == rdm.PIDDMXStartAddress:
+			w.startAddress = int(binary.BigEndian.Uint16(request.ParameterData))
+		case request.CommandClass == rdm.CommandSet && request.PID == rdm.PIDIdentifyDevice:
+			w.identify = request.ParameterData[0] == 1
+		default:
+			response.PortID = rdm.ResponseNack
+			response.ParameterData = []byte{0, 0}
+		}
+		packet, err := response.MarshalBinary()
+		if err != nil {
+			w.t.Errorf("encoding RDM response failed: %v", err)
+			return timeout, nil
+		}
+		return labels["RX_DMX_PACKET"], append([]byte{0}, packet...)
+	case label == labels["TX_RDM_PACKET_REQUEST"]:
+		return timeout, nil
+	default:
+		w.t.Errorf("unexpected widget message [%d]", label)
+		return 0, nil
+	}
+}
+
+func TestRDMThroughWidgetOnPseudoTerminal(t *testing.T) {
+	master, slave := openPTY(t)
+
+	conf := DefaultConfig()
+	conf.ReadTimeout = time.Second
+	widget, err := NewDMXConnection(slave, conf)
+	if err != nil {
+		t.Fatalf("NewDMXConnection() error = %v", err)
+	}
+	defer widget.Close()
+
+	uid := rdm.UID{0x45, 0x4E, 0x12, 0x34, 0x56, 0x78}
+	emulator := &widgetEmulator{t: t, line: master, uid: uid, startAddress: 1}
+	go emulator.run()
+
+	controller := rdm.NewController(widget)
+	uids, err := controller.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(uids) != 1 || uids[0] != uid {
+		t.Fatalf("Discover() = %v, want [%s]", uids, uid)
+	}
+
+	err = controller.SetStartAddress(uid, 42)
+	if err != nil {
+		t.Fatalf("SetStartAddress() error = %v", err)
+	}
+	err = controller.Identify(uid, true)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+	emulator.mutex.Lock()
+	identify := emulator.identify
+	emulator.mutex.Unlock()
+	if !identify {
+		t.Errorf("responder is not switched to identify mode")
+	}
+
+	responder, err := controller.Describe(uid)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	want := rdm.Responder{UID: uid, Manufacturer: "Acme", Model: "0x0102", StartAddress: 42, Footprint: 3}
+	if responder != want {
+		t.Errorf("Describe() = %+v, want %+v", responder, want)
+	}
+
+	_, err = controller.Describe(rdm.UID{0x45, 0x4E, 0x00, 0x00, 0x00, 0x01})
+	if err == nil {
+		t.Errorf("Describe() of missing responder succeeded")
+	}
+}
@@ -0,0 +1,130 @@
+package dmx
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+const (
+	OPEN_DMX_BAUD       = 250000
+	OPEN_DMX_BREAK_TIME = 176 * time.Microsecond
+	OPEN_DMX_MAB_TIME   = 20 * time.Microsecond
+)
+
+// Serial DMX driver sending frames to widget.
+type Driver interface {
+	SetChannel(channel int, val byte) error
+	Render() error
+	Close() error
+}
+
+// Serial port able to hold line in break condition.
+type BreakPort interface {
+	io.WriteCloser
+	SetBreak(on bool) error
+	Drain() error // waits until written bytes are transmitted
+}
+
+// A raw serial DMX connection of Open DMX style adapter (FTDI chip and RS-485 driver).
+// Host generates break, mark-after-break and start code itself on 250 kbaud 8N2 port.
+type OpenDMX struct {
+	dev     string
+	claimed string // resolved port released on close, empty if port is opened by caller
+	frame   [FRAME_SIZE]byte
+	port    BreakPort
+	conf    Config
+}
+
+// Creates a new Open DMX connection using a serial device.
+func NewOpenDMXConnection(device string, conf Config) (*OpenDMX, error) {
+	if device == "" {
+		device = DEV
+	}
+	claimed, ok := claimPort(device)
+	if !ok {
+		return nil, fmt.Errorf("port [%s] is already opened", device)
+	}
+
+	port, err := openBreakPort(device, OPEN_DMX_BAUD)
+	if err != nil {
+		releasePort(claimed)
+		return nil, err
+	}
+
+	dmx, err := NewOpenDMX(device, port, conf)
+	if err != nil {
+		port.Close()
+		releasePort(claimed)
+		return nil, err
+	}
+	dmx.claimed = claimed
+	log.Printf("Opened port [%s].", device)
+	return dmx, nil
+}
+
+// Creates a new Open DMX connection using opened serial port, e.g. pseudo-terminal.
+func NewOpenDMX(device string, port BreakPort, conf Config) (*OpenDMX, error) {
+	if conf.Slots < MIN_FRAME_SIZE || conf.Slots > FRAME_SIZE {
+		return nil, fmt.Errorf("invalid number of slots [%d]", conf.Slots)
+	}
+	if conf.BreakTime <= 0 {
+		conf.BreakTime = OPEN_DMX_BREAK_TIME
+	}
+	if conf.MABTime <= 0 {
+		conf.MABTime = OPEN_DMX_MAB_TIME
+	}
+
+	return &OpenDMX{
+		dev:  device,
+		port: port,
+		conf: conf,
+	}, nil
+}
+
+// Set channel level in the dmx frame to be rendered
+// the next time Render() is called.
+func (dmx *OpenDMX) SetChannel(channel int, val byte) error {
+	err := checkChannelID(channel)
+	if err != nil {
+		return err
+	}
+
+	dmx.frame[channel] = val
+	return nil
+}
+
+// Send break, mark-after-break, start code and frame to serial device.
+// Previous frame is drained first, break doesn't wait for output queue and would cut its last slots.
+func (dmx *OpenDMX) Render() error {
+	err := dmx.port.Drain()
+	if err != nil {
+		return err
+	}
+
+	err = dmx.port.SetBreak(true)
+	if err != nil {
+		return err
+	}
+	time.Sleep(dmx.conf.BreakTime)
+
+	err = dmx.port.SetBreak(false)
+	if err != nil {
+		return err
+	}
+	time.Sleep(dmx.conf.MABTime)
+
+	p := make([]byte, 0, dmx.conf.Slots+1)
+	p = append(p, 0)
+	p = append(p, dmx.frame[:dmx.conf.Slots]...)
+	return writeWithTimeout(dmx.port, p, dmx.conf.WriteTimeout, dmx.dev)
+}
+
+// Close serial port.
+func (dmx *OpenDMX) Close() error {
+	if dmx.claimed != "" {
+		releasePort(dmx.claimed)
+	}
+	return dmx.port.Close()
+}
@@ -0,0 +1,99 @@
+package dmx
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Representation of serial port recording order of line operations
+type fakeBreakPort struct {
+	mutex  sync.Mutex
+	events []string
+	writes [][]byte
+}
+
+func (p *fakeBreakPort) record(event string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *fakeBreakPort) Write(data []byte) (int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.events = append(p.events, fmt.Sprintf("write %d", len(data)))
+	p.writes = append(p.writes, append([]byte(nil), data...))
+	return len(data), nil
+}
+
+func (p *fakeBreakPort) SetBreak(on bool) error {
+	if on {
+		p.record("break on")
+	} else {
+		p.record("break off")
+	}
+	return nil
+}
+
+func (p *fakeBreakPort) Drain() error {
+	p.record("drain")
+	return nil
+}
+
+func (p *fakeBreakPort) Close() error {
+	p.record("close")
+	return nil
+}
+
+func TestOpenDMXRenderSequence(t *testing.T) {
+	tests := []struct {
+		name         string
+		writeTimeout time.Duration
+		slots        int
+	}{
+		{name: "full universe", slots: FRAME_SIZE},
+		{name: "short frame with write timeout", writeTimeout: time.Second, slots: MIN_FRAME_SIZE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := &fakeBreakPort{}
+			conf := DefaultConfig()
+			conf.WriteTimeout = tt.writeTimeout
+			conf.Slots = tt.slots
+			dmx, err := NewOpenDMX("fake", port, conf)
+			if err != nil {
+				t.Fatalf("NewOpenDMX() error = %v", err)
+			}
+
+			for frame := 1; frame <= 3; frame++ {
+				dmx.SetChannel(0, byte(frame))
+				dmx.SetChannel(tt.slots-1, byte(frame))
+				err = dmx.Render()
+				if err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+			}
+
+			var want []string
+			for frame := 1; frame <= 3; frame++ {
+				want = append(want, "drain", "break on", "break off", fmt.Sprintf("write %d", tt.slots+1))
+			}
+			if fmt.Sprint(port.events) != fmt.Sprint(want) {
+				t.Fatalf("events = %v, want %v", port.events, want)
+			}
+
+			for idx, data := range port.writes {
+				frame := make([]byte, tt.slots+1)
+				frame[1] = byte(idx + 1)
+				frame[tt.slots] = byte(idx + 1)
+				if !bytes.Equal(data, frame) {
+					t.Errorf("frame %d = %v, want start code and slots %v", idx+1, data, frame)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+//go:build 386 || amd64 || arm || arm64
+
+package dmx
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	tcgets2  = 0x802C542A
+	tcsets2  = 0x402C542B
+	tcsbrk   = 0x5409 // with non-zero argument works as tcdrain
+	tiocsbrk = 0x5427
+	tioccbrk = 0x5428
+	bother   = 0010000
+)
+
+// Linux termios2 structure allowing arbitrary baud rate.
+type termios2 struct {
+	Iflag  uint32
+	Oflag  uint32
+	Cflag  uint32
+	Lflag  uint32
+	Line   uint8
+	Cc     [19]uint8
+	Ispeed uint32
+	Ospeed uint32
+}
+
+// Serial port of Linux tty device.
+type ttyPort struct {
+	*os.File
+}
+
+// Open tty device in raw 8N2 mode with arbitrary baud rate.
+func openBreakPort(name string, baud int) (BreakPort, error) {
+	f, err := os.OpenFile(name, syscall.O_RDWR|syscall.O_NOCTTY|syscall.O_NONBLOCK, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	t := termios2{}
+	err = ioctl(f.Fd(), tcgets2, uintptr(unsafe.Pointer(&t)))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	t.Iflag = syscall.IGNPAR
+	t.Oflag = 0
+	t.Lflag = 0
+	t.Cflag = syscall.CS8 | syscall.CSTOPB | syscall.CREAD | syscall.CLOCAL | bother
+	t.Ispeed = uint32(baud)
+	t.Ospeed = uint32(baud)
+	err = ioctl(f.Fd(), tcsets2, uintptr(unsafe.Pointer(&t)))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	err = syscall.SetNonblock(int(f.Fd()), false)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &ttyPort{File: f}, nil
+}
+
+// Hold or release line in break condition.
+func (p *ttyPort) SetBreak(on bool) error {
+	request := uintptr(tioccbrk)
+	if on {
+		request = tiocsbrk
+	}
+	return ioctl(p.Fd(), request, 0)
+}
+
+// Wait until output queue of tty device is transmitted.
+func (p *ttyPort) Drain() error {
+	return ioctl(p.Fd(), tcsbrk, 1)
+}
+
+func ioctl(fd uintptr, request uintptr, arg uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+//go:build 386 || amd64 || arm || arm64
+
+package dmx
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestOpenDMXFramesOnPseudoTerminal(t *testing.T) {
+	master, slave := openPTY(t)
+
+	conf := DefaultConfig()
+	conf.Slots = MIN_FRAME_SIZE
+	conf.WriteTimeout = time.Second
+	dmx, err := NewOpenDMXConnection(slave, conf)
+	if err != nil {
+		t.Fatalf("NewOpenDMXConnection() error = %v", err)
+	}
+
+	for frame := 1; frame <= 3; frame++ {
+		dmx.SetChannel(0, byte(frame))
+		dmx.SetChannel(conf.Slots-1, byte(0xF0+frame))
+		err = dmx.Render()
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+
+		received := make([]byte, conf.Slots+1)
+		_, err = io.ReadFull(master, received)
+		if err != nil {
+			t.Fatalf("reading frame %d from line failed: %v", frame, err)
+		}
+		want := make([]byte, conf.Slots+1)
+		want[1] = byte(frame)
+		want[conf.Slots] = byte(0xF0 + frame)
+		if !bytes.Equal(received, want) {
+			t.Errorf("frame %d on line = %v, want start code and slots %v", frame, received, want)
+		}
+	}
+
+	if _, err := NewOpenDMXConnection(slave, conf); err == nil {
+		t.Errorf("port is opened twice")
+	}
+	err = dmx.Close()
+	if err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	reopened, err := NewOpenDMXConnection(slave, conf)
+	if err != nil {
+		t.Fatalf("port is not released on close: %v", err)
+	}
+	reopened.Close()
+}
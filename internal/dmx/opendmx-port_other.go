@@ -0,0 +1,13 @@
+//go:build !linux || !(386 || amd64 || arm || arm64)
+
+package dmx
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Open DMX adapters require raw break control which is implemented only for Linux.
+func openBreakPort(name string, baud int) (BreakPort, error) {
+	return nil, fmt.Errorf("Open DMX driver is not supported on %s/%s", runtime.GOOS, runtime.GOARCH)
+}
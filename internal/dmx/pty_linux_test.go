@@ -0,0 +1,34 @@
+//go:build 386 || amd64 || arm || arm64
+
+package dmx
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// Function opens pseudo-terminal, returns its master side and path of its slave side used as serial port
+func openPTY(t *testing.T) (*os.File, string) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo-terminals are not available: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	unlock := int32(0)
+	err = ioctl(master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock)))
+	if err != nil {
+		t.Fatalf("unlocking pseudo-terminal failed: %v", err)
+	}
+	number := uint32(0)
+	err = ioctl(master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&number)))
+	if err != nil {
+		t.Fatalf("reading pseudo-terminal number failed: %v", err)
+	}
+	return master, fmt.Sprintf("/dev/pts/%d", number)
+}
@@ -0,0 +1,185 @@
+package effects
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	WaveformSine     = "sine"
+	WaveformSquare   = "square"
+	WaveformSaw      = "saw"
+	WaveformTriangle = "triangle"
+	WaveformRandom   = "random"
+	WaveformStrobe   = "strobe"
+
+	StrobeDutyCycle = 0.1
+)
+
+// Representation of waveform generator mapping period position [0:1) to output level [0:1]
+type Generator func(position float64, cycle int64) float64
+
+var generators = map[string]Generator{
+	WaveformSine: func(position float64, _ int64) float64 {
+		return (1 + math.Sin(2*math.Pi*position)) / 2
+	},
+	WaveformSquare: func(position float64, _ int64) float64 {
+		if position < 0.5 {
+			return 1
+		}
+		return 0
+	},
+	WaveformSaw: func(position float64, _ int64) float64 {
+		return position
+	},
+	WaveformTriangle: func(position float64, _ int64) float64 {
+		return 1 - math.Abs(2*position-1)
+	},
+	WaveformRandom: func(_ float64, cycle int64) float64 {
+		return sample(uint64(cycle))
+	},
+	WaveformStrobe: func(position float64, _ int64) float64 {
+		if position < StrobeDutyCycle {
+			return 1
+		}
+		return 0
+	},
+}
+
+// Function returns pseudo-random level [0:1) of period, stable for the whole period (splitmix64)
+func sample(cycle uint64) float64 {
+	z := cycle + 0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	z ^= z >> 31
+	return float64(z>>11) / (1 << 53)
+}
+
+// Function returns waveform generator by name
+func ParseWaveform(name string) (Generator, error) {
+	generator, ok := generators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown waveform '%s' (expected '%s', '%s', '%s', '%s', '%s' or '%s')",
+			name, WaveformSine, WaveformSquare, WaveformSaw, WaveformTriangle, WaveformRandom, WaveformStrobe)
+	}
+	return generator, nil
+}
+
+// Representation of universe channel driven by effect, 16-bit channel is read and written with both bytes
+type Channel interface {
+	Read(universe *[512]byte) int
+	Write(universe *[512]byte, value int)
+	MaxValue() int
+}
+
+// Representation of effect entity bound to single universe channel
+type Effect struct {
+	Channel   Channel
+	Waveform  string
+	Generator Generator
+	Amplitude int
+	Offset    int
+	Period    time.Duration
+	Phase     float64
+	StartedAt time.Time
+	Seed      int64
+}
+
+// Function initializes effect entity, phase is specified in degrees.
+// Amplitude and offset are specified in 8-bit steps and are scaled to full range of 16-bit channel.
+func NewEffect(channel Channel, waveform string, amplitude int, offset int, periodMs int, phase int, startedAt time.Time) (*Effect, error) {
+	generator, err := ParseWaveform(waveform)
+	if err != nil {
+		return nil, err
+	}
+	if periodMs <= 0 {
+		return nil, fmt.Errorf("effect period '%d' should be positive", periodMs)
+	}
+	if amplitude < -255 || amplitude > 255 {
+		return nil, fmt.Errorf("effect amplitude '%d' out of range [-255, 255]", amplitude)
+	}
+	if offset < -255 || offset > 255 {
+		return nil, fmt.Errorf("effect offset '%d' out of range [-255, 255]", offset)
+	}
+
+	return &Effect{
+		Channel:   channel,
+		Waveform:  waveform,
+		Generator: generator,
+		Amplitude: amplitude,
+		Offset:    offset,
+		Period:    time.Duration(periodMs) * time.Millisecond,
+		Phase:     float64(phase%360) / 360,
+		StartedAt: startedAt,
+		Seed:      rand.Int63(),
+	}, nil
+}
+
+// Function returns deviation of effect from base channel value at specified moment, in 8-bit steps
+func (e *Effect) ValueAt(now time.Time) float64 {
+	cycles := float64(now.Sub(e.StartedAt))/float64(e.Period) + e.Phase
+	if cycles < 0 {
+		cycles += math.Ceil(-cycles)
+	}
+	cycle, position := math.Modf(cycles)
+
+	return float64(e.Offset) + float64(e.Amplitude)*e.Generator(position, e.Seed+int64(cycle))
+}
+
+// Function combines effect with base channel value of frame at specified moment, result is clamped to channel range
+func (e *Effect) Apply(now time.Time, frame *[512]byte) {
+	maxValue := e.Channel.MaxValue()
+	value := float64(e.Channel.Read(frame)) + e.ValueAt(now)*float64(maxValue)/255
+	e.Channel.Write(frame, int(math.Max(0, math.Min(float64(maxValue), math.Round(value)))))
+}
+
+// Representation of effect engine entity of single device
+type Engine struct {
+	Effects map[int]*Effect
+}
+
+// Function initializes effect engine entity
+func NewEngine() *Engine {
+	return &Engine{
+		Effects: make(map[int]*Effect),
+	}
+}
+
+// Function binds effect to universe channel replacing running effect of the same channel
+func (e *Engine) Start(universeChannelID int, effect *Effect) {
+	e.Effects[universeChannelID] = effect
+}
+
+// Function stops effect bound to universe channel
+func (e *Engine) Stop(universeChannelID int) {
+	delete(e.Effects, universeChannelID)
+}
+
+// Function keeps effects of universe channels present in channels and binds them to these channels,
+// effects of other universe channels are stopped
+func (e *Engine) Retain(channels map[int]Channel) {
+	for universeChannelID, effect := range e.Effects {
+		channel, ok := channels[universeChannelID]
+		if !ok {
+			delete(e.Effects, universeChannelID)
+			continue
+		}
+		effect.Channel = channel
+	}
+}
+
+// Function stops all running effects
+func (e *Engine) StopAll() {
+	for universeChannelID := range e.Effects {
+		delete(e.Effects, universeChannelID)
+	}
+}
+
+// Function combines running effects with base values of output frame
+func (e *Engine) Apply(now time.Time, frame *[512]byte) {
+	for _, effect := range e.Effects {
+		effect.Apply(now, frame)
+	}
+}
@@ -0,0 +1,198 @@
+package effects
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Representation of 8-bit or 16-bit (coarse at channel, fine at channel + 1) test channel
+type testChannel struct {
+	channel int
+	wide    bool
+}
+
+// Function reads value of test channel from universe
+func (c testChannel) Read(universe *[512]byte) int {
+	if c.wide {
+		return int(universe[c.channel])<<8 | int(universe[c.channel+1])
+	}
+	return int(universe[c.channel])
+}
+
+// Function writes value of test channel to universe
+func (c testChannel) Write(universe *[512]byte, value int) {
+	if c.wide {
+		universe[c.channel] = byte(value >> 8)
+		universe[c.channel+1] = byte(value)
+		return
+	}
+	universe[c.channel] = byte(value)
+}
+
+// Function returns maximum value of test channel
+func (c testChannel) MaxValue() int {
+	if c.wide {
+		return 65535
+	}
+	return 255
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		waveform string
+		position float64
+		want     float64
+	}{
+		{WaveformSine, 0, 0.5},
+		{WaveformSine, 0.25, 1},
+		{WaveformSine, 0.75, 0},
+		{WaveformSquare, 0.25, 1},
+		{WaveformSquare, 0.5, 0},
+		{WaveformSaw, 0, 0},
+		{WaveformSaw, 0.4, 0.4},
+		{WaveformTriangle, 0, 0},
+		{WaveformTriangle, 0.5, 1},
+		{WaveformTriangle, 0.75, 0.5},
+		{WaveformStrobe, 0.05, 1},
+		{WaveformStrobe, StrobeDutyCycle, 0},
+	}
+
+	for _, tt := range tests {
+		generator, err := ParseWaveform(tt.waveform)
+		if err != nil {
+			t.Fatalf("ParseWaveform(%q) error = %v", tt.waveform, err)
+		}
+		if got := generator(tt.position, 0); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s(%v) = %v, want %v", tt.waveform, tt.position, got, tt.want)
+		}
+	}
+}
+
+func TestRandomGeneratorIsStableWithinPeriod(t *testing.T) {
+	generator, err := ParseWaveform(WaveformRandom)
+	if err != nil {
+		t.Fatalf("ParseWaveform() error = %v", err)
+	}
+
+	for cycle := int64(0); cycle < 100; cycle++ {
+		level := generator(0, cycle)
+		if level < 0 || level >= 1 {
+			t.Fatalf("random level %v of cycle %d out of range [0:1)", level, cycle)
+		}
+		if generator(0.9, cycle) != level {
+			t.Fatalf("random level changes within cycle %d", cycle)
+		}
+	}
+	if generator(0, 1) == generator(0, 2) {
+		t.Errorf("random level doesn't change between cycles")
+	}
+}
+
+func TestNewEffectValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		waveform  string
+		amplitude int
+		offset    int
+		periodMs  int
+	}{
+		{name: "unknown waveform", waveform: "noise", amplitude: 10, periodMs: 1000},
+		{name: "zero period", waveform: WaveformSine, amplitude: 10, periodMs: 0},
+		{name: "amplitude out of range", waveform: WaveformSine, amplitude: 256, periodMs: 1000},
+		{name: "offset out of range", waveform: WaveformSine, offset: -256, periodMs: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEffect(testChannel{}, tt.waveform, tt.amplitude, tt.offset, tt.periodMs, 0, time.Now())
+			if err == nil {
+				t.Errorf("NewEffect() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestEffectValueAtPhase(t *testing.T) {
+	startedAt := time.Now()
+	effect, err := NewEffect(testChannel{}, WaveformSaw, 100, 10, 1000, 90, startedAt)
+	if err != nil {
+		t.Fatalf("NewEffect() error = %v", err)
+	}
+
+	tests := []struct {
+		elapsed time.Duration
+		want    float64
+	}{
+		{0, 35},
+		{250 * time.Millisecond, 60},
+		{750 * time.Millisecond, 10},
+		{1250 * time.Millisecond, 60},
+	}
+	for _, tt := range tests {
+		if got := effect.ValueAt(startedAt.Add(tt.elapsed)); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("ValueAt(+%v) = %v, want %v", tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestEffectApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		channel   testChannel
+		base      int
+		amplitude int
+		offset    int
+		want      int
+	}{
+		{name: "adds deviation to channel value", channel: testChannel{channel: 0}, base: 100, amplitude: 50, want: 150},
+		{name: "negative deviation", channel: testChannel{channel: 0}, base: 100, amplitude: -50, offset: -10, want: 40},
+		{name: "clamped to maximum", channel: testChannel{channel: 0}, base: 200, amplitude: 100, want: 255},
+		{name: "clamped to zero", channel: testChannel{channel: 0}, base: 20, amplitude: -100, want: 0},
+		{name: "16-bit deviation is scaled", channel: testChannel{channel: 0, wide: true}, base: 1000, amplitude: 1, want: 1257},
+		{name: "16-bit clamped to maximum", channel: testChannel{channel: 0, wide: true}, base: 65000, amplitude: 10, want: 65535},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startedAt := time.Now()
+			effect, err := NewEffect(tt.channel, WaveformSquare, tt.amplitude, tt.offset, 1000, 0, startedAt)
+			if err != nil {
+				t.Fatalf("NewEffect() error = %v", err)
+			}
+
+			var frame [512]byte
+			tt.channel.Write(&frame, tt.base)
+			effect.Apply(startedAt, &frame)
+			if got := tt.channel.Read(&frame); got != tt.want {
+				t.Errorf("channel value = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineRetain(t *testing.T) {
+	startedAt := time.Now()
+	engine := NewEngine()
+	for _, universeChannelID := range []int{1, 2} {
+		effect, err := NewEffect(testChannel{channel: universeChannelID}, WaveformSquare, 50, 0, 1000, 0, startedAt)
+		if err != nil {
+			t.Fatalf("NewEffect() error = %v", err)
+		}
+		engine.Start(universeChannelID, effect)
+	}
+
+	wide := testChannel{channel: 1, wide: true}
+	engine.Retain(map[int]Channel{1: wide})
+
+	if _, ok := engine.Effects[2]; ok {
+		t.Errorf("effect of channel missing from scene keeps running")
+	}
+	effect, ok := engine.Effects[1]
+	if !ok {
+		t.Fatalf("effect of channel of scene is stopped")
+	}
+	if effect.Channel != Channel(wide) {
+		t.Errorf("effect is not bound to channel of scene")
+	}
+}
@@ -0,0 +1,140 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+)
+
+// Representation of device group entity applying group scenes to all member devices at once
+type Group struct {
+	Alias        string
+	Devices      []*device.BaseDevice
+	Scenes       map[string][]device.ScenePart
+	CurrentScene string
+	signals      chan core.Signal
+	logger       *zap.Logger
+	mutex        sync.Mutex
+}
+
+// Function initializes device group entity from user configuration, channel maps are resolved with patches of member devices
+func NewGroup(conf device.DeviceGroupConfig, devices map[string]device.Device, signals chan core.Signal, logger *zap.Logger) (*Group, error) {
+	members := make(map[string]*device.BaseDevice)
+	group := &Group{
+		Alias:   conf.Alias,
+		Devices: make([]*device.BaseDevice, 0, len(conf.Devices)),
+		Scenes:  make(map[string][]device.ScenePart),
+		signals: signals,
+		logger:  logger.With(zap.String("device_group", conf.Alias)),
+	}
+	for _, deviceAlias := range conf.Devices {
+		dev, ok := devices[deviceAlias]
+		if !ok {
+			return nil, fmt.Errorf("device '%s' of group is not available", deviceAlias)
+		}
+		members[deviceAlias] = dev.GetBaseDevice()
+		group.Devices = append(group.Devices, members[deviceAlias])
+	}
+
+	for _, sceneConfig := range conf.Scenes {
+		parts := make([]device.ScenePart, 0, len(sceneConfig.Devices))
+		for _, partConfig := range sceneConfig.Devices {
+			member := members[partConfig.DeviceAlias]
+			part := device.ScenePart{Device: member, SceneAlias: partConfig.SceneAlias}
+			if partConfig.SceneAlias == "" {
+				partScenes := device.ReadScenesFromDeviceConfig([]device.SceneConfig{{Alias: sceneConfig.Alias, ChannelMap: partConfig.ChannelMap}}, member.Patch)
+				scene := partScenes[sceneConfig.Alias]
+				part.Scene = &scene
+			}
+			parts = append(parts, part)
+		}
+		group.Scenes[sceneConfig.Alias] = parts
+	}
+	return group, nil
+}
+
+// Function sets scene of device group on all member devices with the same fade, single scene changed signal is sent for group
+func (g *Group) SetScene(ctx context.Context, command models.SetScene) error {
+	parts, ok := g.Scenes[command.SceneAlias]
+	if !ok {
+		return fmt.Errorf("invalid scene alias '%s'", command.SceneAlias)
+	}
+	_, err := device.ParseFade(command.FadeMs, "")
+	if err != nil {
+		return err
+	}
+	_, err = device.ParseFade(command.FadeOutMs, "")
+	if err != nil {
+		return err
+	}
+	if command.FadeOutMs == 0 {
+		command.FadeOutMs = command.FadeMs
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	err = device.ApplyGroupScene(parts, command.FadeMs, command.FadeOutMs, func() {
+		for _, part := range parts {
+			part.Device.SaveUniverseToCache(ctx)
+		}
+		g.sendSignal(models.SceneChanged{DeviceAlias: g.Alias, SceneAlias: command.SceneAlias})
+	})
+	if err != nil {
+		return err
+	}
+
+	g.CurrentScene = command.SceneAlias
+	for _, part := range parts {
+		part.Device.SaveUniverseToCache(ctx)
+	}
+	return nil
+}
+
+// Function sends current scene of device group as current scene signal
+func (g *Group) GetCurrentScene(_ context.Context) error {
+	g.mutex.Lock()
+	sceneAlias := g.CurrentScene
+	g.mutex.Unlock()
+
+	g.sendSignal(models.CurrentScene{DeviceAlias: g.Alias, SceneAlias: sceneAlias})
+	return nil
+}
+
+// Function sends scenes of device group as scene list signal
+func (g *Group) ListScenes(_ context.Context) error {
+	g.mutex.Lock()
+	signal := models.SceneList{
+		DeviceAlias:  g.Alias,
+		Scenes:       make([]string, 0, len(g.Scenes)),
+		CurrentScene: g.CurrentScene,
+	}
+	g.mutex.Unlock()
+
+	for sceneAlias := range g.Scenes {
+		signal.Scenes = append(signal.Scenes, sceneAlias)
+	}
+	sort.Strings(signal.Scenes)
+	g.sendSignal(signal)
+	return nil
+}
+
+// Function sends signal of device group without blocking caller, signal is sent from separate goroutine
+// if signal channel is full. Scene changed signal is sent from completion callback called under group mutex
+// or from output loop of member device, so it must never block.
+func (g *Group) sendSignal(signal core.Signal) {
+	select {
+	case g.signals <- signal:
+	default:
+		go func() {
+			g.signals <- signal
+		}()
+	}
+}
@@ -0,0 +1,59 @@
+package group
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+)
+
+func TestSetSceneDoesNotBlockOnFullSignalChannel(t *testing.T) {
+	persister := device.NewPersister(device.NewMemoryStore(), 0, zap.NewNop())
+	t.Cleanup(persister.Close)
+	scenes := []device.SceneConfig{{Alias: "warm", ChannelMap: []device.ChannelMapConfig{{SceneChannelID: 0, UniverseChannelID: 0, Value: 100}}}}
+	member := device.NewBaseDevice(context.Background(), "member", nil, scenes, nil, 0, 0, make(chan core.Signal, 1), zap.NewNop(), nil, persister)
+	member.Connected.Store(true)
+
+	signals := make(chan core.Signal)
+	g := &Group{
+		Alias:   "group",
+		Devices: []*device.BaseDevice{member},
+		Scenes:  map[string][]device.ScenePart{"warm": {{Device: member, SceneAlias: "warm"}}},
+		signals: signals,
+		logger:  zap.NewNop(),
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; i < 3; i++ {
+			err := g.SetScene(context.Background(), models.SetScene{DeviceAlias: "group", SceneAlias: "warm"})
+			if err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- g.GetCurrentScene(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("SetScene() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("group commands are blocked by full signal channel")
+	}
+
+	for i := 0; i < 4; i++ {
+		select {
+		case <-signals:
+		case <-time.After(time.Second):
+			t.Fatalf("signal %d is not delivered", i)
+		}
+	}
+}
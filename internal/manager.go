@@ -3,22 +3,39 @@ package internal
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"git.miem.hse.ru/hubman/hubman-lib/core"
 
 	"git.miem.hse.ru/hubman/dmx-executor/internal/artnet"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/cue"
 	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
 	"git.miem.hse.ru/hubman/dmx-executor/internal/dmx"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/group"
 	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/sacn"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/virtual"
 	"go.uber.org/zap"
 )
 
+// Number of signals queued, when queue is full device snapshots are dropped and other signals wait in separate goroutines
+const signalBufferSize = 256
+
 // Function initializes device manager entity
 func NewManager(logger *zap.Logger, checkManager core.CheckRegistry) *manager {
 	return &manager{
-		devices: make(map[string]device.Device),
-		signals: make(chan core.Signal),
-		logger:  logger,
+		devices:      make(map[string]device.Device),
+		groups:       make(map[string]*group.Group),
+		cueLists:     make(map[string]*cue.Player),
+		receiver:     nil,
+		store:        nil,
+		persister:    nil,
+		storeConfig:  device.StorageConfig{},
+		snapshotStop: nil,
+		snapshotDone: nil,
+		signals:      make(chan core.Signal, signalBufferSize),
+		logger:       logger,
 		checkManager: checkManager,
 	}
 }
@@ -26,9 +43,18 @@ func NewManager(logger *zap.Logger, checkManager core.CheckRegistry) *manager {
 // Representation of device manager entity
 type manager struct {
 	devices      map[string]device.Device
+	groups       map[string]*group.Group
+	cueLists     map[string]*cue.Player
+	receiver     *artnet.Receiver
+	store        device.Store
+	persister    *device.Persister
+	storeConfig  device.StorageConfig
+	snapshotStop chan struct{}
+	snapshotDone chan struct{}
 	signals      chan core.Signal
 	logger       *zap.Logger
 	checkManager core.CheckRegistry
+	mutex        sync.RWMutex // guards devices, groups and cueLists, not held while commands are processed
 }
 
 // Function returns signal channel value of device manager
@@ -38,15 +64,34 @@ func (m *manager) GetSignals() chan core.Signal {
 
 // Function returns current device list of device manager
 func (m *manager) GetDevices() map[string]device.Device {
-	return m.devices
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	devices := make(map[string]device.Device, len(m.devices))
+	for alias, dev := range m.devices {
+		devices[alias] = dev
+	}
+	return devices
 }
 
 // Function updates current device list of device manager
 func (m *manager) UpdateDevices(ctx context.Context, userConfig device.UserConfig) {
 	dmxDeviceConfig := userConfig.DMXDevices
 	artnetDeviceConfig := userConfig.ArtNetDevices
+	sacnDeviceConfig := userConfig.SACNDevices
+	virtualDeviceConfig := userConfig.VirtualDevices
 
 	m.checkManager.Clear()
+	m.stopSnapshots()
+	m.closeCueLists()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.receiver != nil {
+		m.receiver.Close()
+		m.receiver = nil
+	}
 
 	for alias := range m.devices {
 		err := m.removeDevice(ctx, alias)
@@ -55,19 +100,203 @@ func (m *manager) UpdateDevices(ctx context.Context, userConfig device.UserConfi
 		}
 	}
 
+	m.updateStore(userConfig.Storage)
+
 	for _, conf := range artnetDeviceConfig {
-		err := m.addArtNet(ctx, conf)
+		err := m.addArtNet(ctx, conf, userConfig.ArtNet, userConfig.FixtureProfiles)
 		if err != nil {
 			m.logger.Error("error while adding new Artnet device", zap.Error(err), zap.Any("conf", conf))
 		}
 	}
 
+	for _, conf := range sacnDeviceConfig {
+		err := m.addSACN(ctx, conf, userConfig.FixtureProfiles)
+		if err != nil {
+			m.logger.Error("error while adding new sACN device", zap.Error(err), zap.Any("conf", conf))
+		}
+	}
+
 	for _, conf := range dmxDeviceConfig {
-		err := m.addDMX(ctx, conf)
+		err := m.addDMX(ctx, conf, userConfig.FixtureProfiles)
 		if err != nil {
 			m.logger.Error("error while adding new DMX device", zap.Error(err), zap.Any("conf", conf))
 		}
 	}
+
+	for _, conf := range virtualDeviceConfig {
+		err := m.addVirtual(ctx, conf, userConfig.FixtureProfiles)
+		if err != nil {
+			m.logger.Error("error while adding new virtual device", zap.Error(err), zap.Any("conf", conf))
+		}
+	}
+
+	m.updateGroups(userConfig.DeviceGroups)
+	m.updateCueLists(userConfig.CueLists)
+	m.updateArtNetInputs(userConfig.ArtNet, userConfig.ArtNetInputs)
+	m.startSnapshots(userConfig.SnapshotInterval)
+}
+
+// Function starts periodic universe snapshot signals of all devices, disabled if interval is not positive
+func (m *manager) startSnapshots(interval int) {
+	if interval <= 0 || len(m.devices) == 0 {
+		return
+	}
+	devices := make([]device.Device, 0, len(m.devices))
+	for _, dev := range m.devices {
+		devices = append(devices, dev)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	m.snapshotStop = stop
+	m.snapshotDone = done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(time.Duration(interval) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				for _, dev := range devices {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					dev.GetBaseDevice().CreateUniverseSnapshotSignal()
+				}
+			}
+		}
+	}()
+}
+
+// Function stops periodic universe snapshot signals
+func (m *manager) stopSnapshots() {
+	if m.snapshotStop == nil {
+		return
+	}
+	close(m.snapshotStop)
+	<-m.snapshotDone
+	m.snapshotStop = nil
+	m.snapshotDone = nil
+}
+
+// Function opens store and persister shared by all devices, store is reopened only when its configuration changes
+func (m *manager) updateStore(conf device.StorageConfig) {
+	if m.store != nil && m.storeConfig == conf {
+		return
+	}
+	m.closeStore()
+
+	store, err := device.NewStore(conf)
+	if err != nil {
+		m.logger.Error("error while opening store, falling back to memory store", zap.Error(err), zap.Any("conf", conf))
+		store = device.NewMemoryStore()
+	}
+	m.store = store
+	m.storeConfig = conf
+	m.persister = device.NewPersister(store, conf.FlushInterval, m.logger)
+}
+
+// Function flushes pending changes and closes store
+func (m *manager) closeStore() {
+	if m.store == nil {
+		return
+	}
+	m.persister.Close()
+	err := m.store.Close()
+	if err != nil {
+		m.logger.Error("error while closing store", zap.Error(err))
+	}
+	m.store = nil
+	m.persister = nil
+}
+
+// Function frees resources of device manager, pending universe changes are written to store
+func (m *manager) Close(ctx context.Context) {
+	m.stopSnapshots()
+	m.closeCueLists()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.receiver != nil {
+		m.receiver.Close()
+		m.receiver = nil
+	}
+	for alias := range m.devices {
+		err := m.removeDevice(ctx, alias)
+		if err != nil {
+			m.logger.Error("error while removing device", zap.Error(err), zap.Any("alias", alias))
+		}
+	}
+	m.closeStore()
+}
+
+// Function starts Artnet receiver feeding configured inputs into devices
+func (m *manager) updateArtNetInputs(controllerConf device.ArtNetControllerConfig, inputConfig []device.ArtNetInputConfig) {
+	if len(inputConfig) == 0 {
+		return
+	}
+
+	inputs := make([]artnet.Input, 0, len(inputConfig))
+	for _, conf := range inputConfig {
+		dev, ok := m.devices[conf.DeviceAlias]
+		if !ok {
+			m.logger.Error("device of Artnet input is not available", zap.Any("conf", conf))
+			continue
+		}
+		inputs = append(inputs, artnet.Input{
+			Net:     uint8(conf.Net),
+			SubUni:  uint8(conf.SubUni),
+			Mode:    conf.Merge,
+			Timeout: time.Duration(conf.Timeout) * time.Millisecond,
+			Device:  dev,
+		})
+	}
+
+	receiver, err := artnet.NewReceiver(controllerConf.InputAddress, inputs, m.logger)
+	if err != nil {
+		m.logger.Error("error while starting Artnet receiver", zap.Error(err))
+		return
+	}
+	m.receiver = receiver
+}
+
+// Function updates current device groups of device manager
+func (m *manager) updateGroups(groupConfig []device.DeviceGroupConfig) {
+	m.groups = make(map[string]*group.Group)
+
+	for _, conf := range groupConfig {
+		newGroup, err := group.NewGroup(conf, m.devices, m.signals, m.logger)
+		if err != nil {
+			m.logger.Error("error while adding device group", zap.Error(err), zap.Any("alias", conf.Alias))
+			continue
+		}
+		m.groups[conf.Alias] = newGroup
+	}
+}
+
+// Function stops pending cues of current cue list players before devices are changed.
+// Players are closed without manager mutex, so that cues being applied can finish.
+func (m *manager) closeCueLists() {
+	m.mutex.Lock()
+	cueLists := m.cueLists
+	m.cueLists = make(map[string]*cue.Player)
+	m.mutex.Unlock()
+
+	for _, player := range cueLists {
+		player.Close()
+	}
+}
+
+// Function updates current cue list players of device manager, caller must hold manager mutex
+func (m *manager) updateCueLists(cueListConfig []device.CueListConfig) {
+	for _, conf := range cueListConfig {
+		m.cueLists[conf.Alias] = cue.NewPlayer(conf, m.ProcessSetScene, m.signals, m.logger)
+	}
 }
 
 // Function processing set channel command
@@ -84,6 +313,34 @@ func (m *manager) ProcessSetChannel(ctx context.Context, command models.SetChann
 	return nil
 }
 
+// Function processing set channels command
+func (m *manager) ProcessSetChannels(ctx context.Context, command models.SetChannels) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.SetChannels(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v setting values error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing set universe command
+func (m *manager) ProcessSetUniverse(ctx context.Context, command models.SetUniverse) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.SetUniverse(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v setting universe error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
 // Function processing increment channel command
 func (m *manager) ProcessIncrementChannel(ctx context.Context, command models.IncrementChannel) error {
 	dev, err := m.checkDevice(command.DeviceAlias)
@@ -98,6 +355,34 @@ func (m *manager) ProcessIncrementChannel(ctx context.Context, command models.In
 	return nil
 }
 
+// Function processing 16-bit set channel command
+func (m *manager) ProcessSetChannel16(ctx context.Context, command models.SetChannel16) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.SetChannel16(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v setting value error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing 16-bit increment channel command
+func (m *manager) ProcessIncrementChannel16(ctx context.Context, command models.IncrementChannel16) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.IncrementChannel16(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v setting value error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
 // Function processing blackout command
 func (m *manager) ProcessBlackout(ctx context.Context, command models.Blackout) error {
 	dev, err := m.checkDevice(command.DeviceAlias)
@@ -112,8 +397,16 @@ func (m *manager) ProcessBlackout(ctx context.Context, command models.Blackout)
 	return nil
 }
 
-// Function processing set scene command
+// Function processing set scene command, scene of device group is set if device alias is group alias
 func (m *manager) ProcessSetScene(ctx context.Context, command models.SetScene) error {
+	if deviceGroup, ok := m.checkGroup(command.DeviceAlias); ok {
+		err := deviceGroup.SetScene(ctx, command)
+		if err != nil {
+			return fmt.Errorf("device group with alias %v setting scene error: %v", deviceGroup.Alias, err)
+		}
+		return nil
+	}
+
 	dev, err := m.checkDevice(command.DeviceAlias)
 	if err != nil {
 		return err
@@ -121,7 +414,7 @@ func (m *manager) ProcessSetScene(ctx context.Context, command models.SetScene)
 
 	err = dev.SetScene(ctx, command)
 	if err != nil {
-		return fmt.Errorf("device with alias %v blackout error: %v", dev.GetAlias(), err)
+		return fmt.Errorf("device with alias %v setting scene error: %v", dev.GetAlias(), err)
 	}
 	return nil
 }
@@ -135,13 +428,253 @@ func (m *manager) ProcessSaveScene(ctx context.Context, command models.SaveScene
 
 	err = dev.SaveScene(ctx)
 	if err != nil {
-		return fmt.Errorf("device with alias %v blackout error: %v", dev.GetAlias(), err)
+		return fmt.Errorf("device with alias %v saving scene error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing learn scene command
+func (m *manager) ProcessLearnScene(ctx context.Context, command models.LearnScene) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.LearnScene(ctx)
+	if err != nil {
+		return fmt.Errorf("device with alias %v learning scene error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing create scene command
+func (m *manager) ProcessCreateScene(ctx context.Context, command models.CreateScene) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.CreateScene(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v creating scene error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing save scene as command
+func (m *manager) ProcessSaveSceneAs(ctx context.Context, command models.SaveSceneAs) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.SaveSceneAs(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v saving scene as error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing rename scene command
+func (m *manager) ProcessRenameScene(ctx context.Context, command models.RenameScene) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.RenameScene(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v renaming scene error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing delete scene command
+func (m *manager) ProcessDeleteScene(ctx context.Context, command models.DeleteScene) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.DeleteScene(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v deleting scene error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing get universe command
+func (m *manager) ProcessGetUniverse(ctx context.Context, command models.GetUniverse) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.GetUniverse(ctx)
+	if err != nil {
+		return fmt.Errorf("device with alias %v getting universe error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing get current scene command, current scene of device group is sent if device alias is group alias
+func (m *manager) ProcessGetCurrentScene(ctx context.Context, command models.GetCurrentScene) error {
+	if deviceGroup, ok := m.checkGroup(command.DeviceAlias); ok {
+		err := deviceGroup.GetCurrentScene(ctx)
+		if err != nil {
+			return fmt.Errorf("device group with alias %v getting current scene error: %v", deviceGroup.Alias, err)
+		}
+		return nil
+	}
+
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.GetCurrentScene(ctx)
+	if err != nil {
+		return fmt.Errorf("device with alias %v getting current scene error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing list scenes command, scenes of device group are sent if device alias is group alias
+func (m *manager) ProcessListScenes(ctx context.Context, command models.ListScenes) error {
+	if deviceGroup, ok := m.checkGroup(command.DeviceAlias); ok {
+		err := deviceGroup.ListScenes(ctx)
+		if err != nil {
+			return fmt.Errorf("device group with alias %v listing scenes error: %v", deviceGroup.Alias, err)
+		}
+		return nil
+	}
+
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.ListScenes(ctx)
+	if err != nil {
+		return fmt.Errorf("device with alias %v listing scenes error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing RDM discovery command
+func (m *manager) ProcessRDMDiscover(ctx context.Context, command models.RDMDiscover) error {
+	dev, err := m.checkRDMDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.DiscoverRDM(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v RDM discovery error: %v", command.DeviceAlias, err)
+	}
+	return nil
+}
+
+// Function processing RDM set start address command
+func (m *manager) ProcessRDMSetStartAddress(ctx context.Context, command models.RDMSetStartAddress) error {
+	dev, err := m.checkRDMDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.SetRDMStartAddress(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v RDM setting start address error: %v", command.DeviceAlias, err)
 	}
 	return nil
 }
 
+// Function processing RDM identify command
+func (m *manager) ProcessRDMIdentify(ctx context.Context, command models.RDMIdentify) error {
+	dev, err := m.checkRDMDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.IdentifyRDM(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v RDM identify error: %v", command.DeviceAlias, err)
+	}
+	return nil
+}
+
+// Function processing start effect command
+func (m *manager) ProcessStartEffect(ctx context.Context, command models.StartEffect) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.StartEffect(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v starting effect error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing stop effect command
+func (m *manager) ProcessStopEffect(ctx context.Context, command models.StopEffect) error {
+	dev, err := m.checkDevice(command.DeviceAlias)
+	if err != nil {
+		return err
+	}
+
+	err = dev.StopEffect(ctx, command)
+	if err != nil {
+		return fmt.Errorf("device with alias %v stopping effect error: %v", dev.GetAlias(), err)
+	}
+	return nil
+}
+
+// Function processing cue go command
+func (m *manager) ProcessCueGo(ctx context.Context, command models.CueGo) error {
+	player, err := m.checkCueList(command.CueListAlias)
+	if err != nil {
+		return err
+	}
+	return player.Go(ctx)
+}
+
+// Function processing cue back command
+func (m *manager) ProcessCueBack(ctx context.Context, command models.CueBack) error {
+	player, err := m.checkCueList(command.CueListAlias)
+	if err != nil {
+		return err
+	}
+	return player.Back(ctx)
+}
+
+// Function processing cue goto command
+func (m *manager) ProcessCueGoto(ctx context.Context, command models.CueGoto) error {
+	player, err := m.checkCueList(command.CueListAlias)
+	if err != nil {
+		return err
+	}
+	return player.Goto(ctx, command.CueIndex)
+}
+
+// Function checks cue lists containing cue list with specified alias
+func (m *manager) checkCueList(cueListAlias string) (*cue.Player, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	player, ok := m.cueLists[cueListAlias]
+	if !ok {
+		return nil, fmt.Errorf("cue list with alias %v not found", cueListAlias)
+	}
+	return player, nil
+}
+
 // Function checks devices list containing device with specified alias
 func (m *manager) checkDevice(deviceAlias string) (device.Device, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
 	dev, devExist := m.devices[deviceAlias]
 	if !devExist {
 		return nil, fmt.Errorf("dmx-device with alias %v not found", deviceAlias)
@@ -150,9 +683,31 @@ func (m *manager) checkDevice(deviceAlias string) (device.Device, error) {
 
 }
 
+// Function returns device group with specified alias if it exists
+func (m *manager) checkGroup(groupAlias string) (*group.Group, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	deviceGroup, ok := m.groups[groupAlias]
+	return deviceGroup, ok
+}
+
+// Function checks existence of device supporting RDM
+func (m *manager) checkRDMDevice(deviceAlias string) (device.RDMDevice, error) {
+	dev, err := m.checkDevice(deviceAlias)
+	if err != nil {
+		return nil, err
+	}
+	rdmDev, ok := dev.(device.RDMDevice)
+	if !ok {
+		return nil, fmt.Errorf("device with alias %v doesn't support RDM", deviceAlias)
+	}
+	return rdmDev, nil
+}
+
 // Function adds DMX device to device list
-func (m *manager) addDMX(ctx context.Context, conf device.DMXConfig) error {
-	newDMX, err := dmx.NewDMXDevice(ctx, m.signals, conf, m.logger, m.checkManager)
+func (m *manager) addDMX(ctx context.Context, conf device.DMXConfig, profiles []device.FixtureProfileConfig) error {
+	newDMX, err := dmx.NewDMXDevice(ctx, m.signals, conf, profiles, m.logger, m.checkManager, m.persister)
 	if err != nil {
 		return fmt.Errorf("error with add device: %v", err)
 	}
@@ -161,8 +716,8 @@ func (m *manager) addDMX(ctx context.Context, conf device.DMXConfig) error {
 }
 
 // Function adds Artnet device to device list
-func (m *manager) addArtNet(ctx context.Context, conf device.ArtNetConfig) error {
-	newArtNet, err := artnet.NewArtNetDevice(ctx, m.signals, conf, m.logger, m.checkManager)
+func (m *manager) addArtNet(ctx context.Context, conf device.ArtNetConfig, controllerConf device.ArtNetControllerConfig, profiles []device.FixtureProfileConfig) error {
+	newArtNet, err := artnet.NewArtNetDevice(ctx, m.signals, conf, controllerConf, profiles, m.logger, m.checkManager, m.persister)
 	if err != nil {
 		return fmt.Errorf("error with add device: %v", err)
 	}
@@ -170,6 +725,26 @@ func (m *manager) addArtNet(ctx context.Context, conf device.ArtNetConfig) error
 	return nil
 }
 
+// Function adds sACN device to device list
+func (m *manager) addSACN(ctx context.Context, conf device.SACNConfig, profiles []device.FixtureProfileConfig) error {
+	newSACN, err := sacn.NewSACNDevice(ctx, m.signals, conf, profiles, m.logger, m.checkManager, m.persister)
+	if err != nil {
+		return fmt.Errorf("error with add device: %v", err)
+	}
+	m.devices[newSACN.GetAlias()] = newSACN
+	return nil
+}
+
+// Function adds virtual device to device list
+func (m *manager) addVirtual(ctx context.Context, conf device.VirtualConfig, profiles []device.FixtureProfileConfig) error {
+	newVirtual, err := virtual.NewVirtualDevice(ctx, m.signals, conf, profiles, m.logger, m.checkManager, m.persister)
+	if err != nil {
+		return fmt.Errorf("error with add device: %v", err)
+	}
+	m.devices[newVirtual.GetAlias()] = newVirtual
+	return nil
+}
+
 // Function removes any device from device list
 func (m *manager) removeDevice(_ context.Context, alias string) error {
 	dev := m.devices[alias]
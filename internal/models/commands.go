@@ -2,9 +2,12 @@ package models
 
 // Represenation of set channel command
 type SetChannel struct {
-	Channel     int    `hubman:"channel"` // up to 512
+	Channel     int    `hubman:"channel"`   // up to 512
+	Attribute   string `hubman:"attribute"` // optional, patched "fixture.attribute", takes precedence over channel
 	Value       int    `hubman:"value"`
 	DeviceAlias string `hubman:"device_alias"`
+	FadeMs      int    `hubman:"fade_ms"` // optional, fade duration in milliseconds
+	Easing      string `hubman:"easing"`  // optional, one of "linear", "ease_in_out", "s_curve"
 }
 
 // Function returns string code of command
@@ -14,14 +17,62 @@ func (s SetChannel) Code() string {
 
 // Function returns string description of command
 func (s SetChannel) Description() string {
-	return "Sending value to chosen channel of single DMX/Artnet device by alias"
+	return "Sending value to chosen channel of single DMX/Artnet device by alias with optional fade"
+}
+
+// Represenation of channel value of set channels command
+type ChannelValue struct {
+	Channel   int    `hubman:"channel"`   // up to 512
+	Attribute string `hubman:"attribute"` // optional, patched "fixture.attribute", takes precedence over channel
+	Value     int    `hubman:"value"`
+}
+
+// Represenation of set channels command, values are applied to device in single frame
+type SetChannels struct {
+	DeviceAlias  string         `hubman:"device_alias"`
+	Channels     []ChannelValue `hubman:"channels"`      // optional, list of channel values
+	StartChannel int            `hubman:"start_channel"` // optional, scene channel of first value of values
+	Values       []int          `hubman:"values"`        // optional, values of consecutive scene channels from start_channel
+	FadeMs       int            `hubman:"fade_ms"`       // optional, fade duration in milliseconds
+	Easing       string         `hubman:"easing"`        // optional, one of "linear", "ease_in_out", "s_curve"
+}
+
+// Function returns string code of command
+func (s SetChannels) Code() string {
+	return "SetChannels"
+}
+
+// Function returns string description of command
+func (s SetChannels) Description() string {
+	return "Sending values to several channels of current scene of single DMX/Artnet device by alias in one frame with optional fade"
+}
+
+// Represenation of set universe command, values are written to universe channels bypassing scene channel map
+type SetUniverse struct {
+	DeviceAlias string `hubman:"device_alias"`
+	Data        string `hubman:"data"`     // base64 channel values or universe RLE encoding of cache
+	Encoding    string `hubman:"encoding"` // optional, "base64" (default) or "rle"
+	Start       int    `hubman:"start"`    // optional, universe channel of first value, [0:511]
+}
+
+// Function returns string code of command
+func (s SetUniverse) Code() string {
+	return "SetUniverse"
+}
+
+// Function returns string description of command
+func (s SetUniverse) Description() string {
+	return "Sending full or partial frame to universe of single DMX/Artnet device by alias if allowed by config, protected channels are kept"
 }
 
 // Represenation of increment channel command
 type IncrementChannel struct {
-	Channel     int    `hubman:"channel"` // up to 512
+	Channel     int    `hubman:"channel"`   // up to 512
+	Attribute   string `hubman:"attribute"` // optional, patched "fixture.attribute", takes precedence over channel
 	Value       int    `hubman:"value"`
 	DeviceAlias string `hubman:"device_alias"`
+	FadeMs      int    `hubman:"fade_ms"` // optional, fade duration in milliseconds
+	Easing      string `hubman:"easing"`  // optional, one of "linear", "ease_in_out", "s_curve"
 }
 
 // Function returns string code of command
@@ -31,7 +82,47 @@ func (i IncrementChannel) Code() string {
 
 // Function returns string description of command
 func (i IncrementChannel) Description() string {
-	return "Incrementing value of chosen channel by specified value of single DMX/Artnet device by alias"
+	return "Incrementing value of chosen channel by specified value of single DMX/Artnet device by alias with optional fade"
+}
+
+// Represenation of 16-bit set channel command
+type SetChannel16 struct {
+	Channel     int    `hubman:"channel"`   // up to 512
+	Attribute   string `hubman:"attribute"` // optional, patched "fixture.attribute", takes precedence over channel
+	Value       int    `hubman:"value"`     // up to 65535
+	DeviceAlias string `hubman:"device_alias"`
+	FadeMs      int    `hubman:"fade_ms"` // optional, fade duration in milliseconds
+	Easing      string `hubman:"easing"`  // optional, one of "linear", "ease_in_out", "s_curve"
+}
+
+// Function returns string code of command
+func (s SetChannel16) Code() string {
+	return "SetChannel16"
+}
+
+// Function returns string description of command
+func (s SetChannel16) Description() string {
+	return "Sending 16-bit value to chosen coarse/fine channel of single DMX/Artnet device by alias with optional fade"
+}
+
+// Represenation of 16-bit increment channel command
+type IncrementChannel16 struct {
+	Channel     int    `hubman:"channel"`   // up to 512
+	Attribute   string `hubman:"attribute"` // optional, patched "fixture.attribute", takes precedence over channel
+	Value       int    `hubman:"value"`
+	DeviceAlias string `hubman:"device_alias"`
+	FadeMs      int    `hubman:"fade_ms"` // optional, fade duration in milliseconds
+	Easing      string `hubman:"easing"`  // optional, one of "linear", "ease_in_out", "s_curve"
+}
+
+// Function returns string code of command
+func (i IncrementChannel16) Code() string {
+	return "IncrementChannel16"
+}
+
+// Function returns string description of command
+func (i IncrementChannel16) Description() string {
+	return "Incrementing 16-bit value of chosen coarse/fine channel by specified value of single DMX/Artnet device by alias with optional fade"
 }
 
 // Represenation of blackout command
@@ -53,6 +144,8 @@ func (b Blackout) Description() string {
 type SetScene struct {
 	DeviceAlias string `hubman:"device_alias"`
 	SceneAlias  string `hubman:"scene_alias"`
+	FadeMs      int    `hubman:"fade_ms"`     // optional, crossfade duration in milliseconds
+	FadeOutMs   int    `hubman:"fade_out_ms"` // optional, duration for decreasing channels, fade_ms is used if not set
 }
 
 // Function returns string code of command
@@ -62,7 +155,7 @@ func (s SetScene) Code() string {
 
 // Function returns string description of command
 func (s SetScene) Description() string {
-	return "Sets scene by alias for single DMX/Artnet device"
+	return "Sets scene by alias for single DMX/Artnet device with optional crossfade"
 }
 
 // Represenation of save scene command
@@ -79,3 +172,270 @@ func (s SaveScene) Code() string {
 func (s SaveScene) Description() string {
 	return "Saves current dmx scene for single DMX/Artnet device"
 }
+
+// Represenation of learn scene command
+type LearnScene struct {
+	DeviceAlias string `hubman:"device_alias"`
+}
+
+// Function returns string code of command
+func (s LearnScene) Code() string {
+	return "LearnScene"
+}
+
+// Function returns string description of command
+func (s LearnScene) Description() string {
+	return "Captures values of current dmx scene channels from device input (DMX input or Artnet input) and saves scene"
+}
+
+// Represenation of scene channel of create scene command
+type SceneChannel struct {
+	SceneChannelID        int    `hubman:"scene_channel_id"`
+	UniverseChannelID     int    `hubman:"universe_channel_id"`
+	FineUniverseChannelID *int   `hubman:"fine_universe_channel_id"` // optional, makes channel 16-bit
+	Attribute             string `hubman:"attribute"`                // optional, patched "fixture.attribute", takes precedence over universe channel
+	Value                 int    `hubman:"value"`                    // optional, [0:255], [0:65535] for 16-bit channel
+}
+
+// Represenation of create scene command
+type CreateScene struct {
+	DeviceAlias string         `hubman:"device_alias"`
+	SceneAlias  string         `hubman:"scene_alias"`
+	ChannelMap  []SceneChannel `hubman:"channel_map"`
+}
+
+// Function returns string code of command
+func (c CreateScene) Code() string {
+	return "CreateScene"
+}
+
+// Function returns string description of command
+func (c CreateScene) Description() string {
+	return "Creates new scene from channel map for single DMX/Artnet device"
+}
+
+// Represenation of save scene as command
+type SaveSceneAs struct {
+	DeviceAlias string `hubman:"device_alias"`
+	SceneAlias  string `hubman:"scene_alias"` // alias of new scene
+}
+
+// Function returns string code of command
+func (s SaveSceneAs) Code() string {
+	return "SaveSceneAs"
+}
+
+// Function returns string description of command
+func (s SaveSceneAs) Description() string {
+	return "Saves current dmx scene with current values under new alias and selects it for single DMX/Artnet device"
+}
+
+// Represenation of rename scene command
+type RenameScene struct {
+	DeviceAlias   string `hubman:"device_alias"`
+	SceneAlias    string `hubman:"scene_alias"`
+	NewSceneAlias string `hubman:"new_scene_alias"`
+}
+
+// Function returns string code of command
+func (r RenameScene) Code() string {
+	return "RenameScene"
+}
+
+// Function returns string description of command
+func (r RenameScene) Description() string {
+	return "Renames scene of single DMX/Artnet device"
+}
+
+// Represenation of delete scene command
+type DeleteScene struct {
+	DeviceAlias string `hubman:"device_alias"`
+	SceneAlias  string `hubman:"scene_alias"`
+}
+
+// Function returns string code of command
+func (d DeleteScene) Code() string {
+	return "DeleteScene"
+}
+
+// Function returns string description of command
+func (d DeleteScene) Description() string {
+	return "Deletes scene of single DMX/Artnet device, current scene can't be deleted"
+}
+
+// Represenation of get universe command
+type GetUniverse struct {
+	DeviceAlias string `hubman:"device_alias"`
+}
+
+// Function returns string code of command
+func (g GetUniverse) Code() string {
+	return "GetUniverse"
+}
+
+// Function returns string description of command
+func (g GetUniverse) Description() string {
+	return "Requests frame sent to single DMX/Artnet device by alias, answer is sent as UniverseSnapshot signal"
+}
+
+// Represenation of get current scene command
+type GetCurrentScene struct {
+	DeviceAlias string `hubman:"device_alias"` // device or device group alias
+}
+
+// Function returns string code of command
+func (g GetCurrentScene) Code() string {
+	return "GetCurrentScene"
+}
+
+// Function returns string description of command
+func (g GetCurrentScene) Description() string {
+	return "Requests current scene of single DMX/Artnet device or device group by alias, answer is sent as CurrentScene signal"
+}
+
+// Represenation of list scenes command
+type ListScenes struct {
+	DeviceAlias string `hubman:"device_alias"` // device or device group alias
+}
+
+// Function returns string code of command
+func (l ListScenes) Code() string {
+	return "ListScenes"
+}
+
+// Function returns string description of command
+func (l ListScenes) Description() string {
+	return "Requests scenes of single DMX/Artnet device or device group by alias, answer is sent as SceneList signal"
+}
+
+// Represenation of RDM discovery command
+type RDMDiscover struct {
+	DeviceAlias string `hubman:"device_alias"`
+}
+
+// Function returns string code of command
+func (r RDMDiscover) Code() string {
+	return "RDMDiscover"
+}
+
+// Function returns string description of command
+func (r RDMDiscover) Description() string {
+	return "Runs RDM discovery on single DMX device, discovered fixtures are sent as RDMResponder signals"
+}
+
+// Represenation of RDM set start address command
+type RDMSetStartAddress struct {
+	DeviceAlias string `hubman:"device_alias"`
+	UID         string `hubman:"uid"`     // "MMMM:DDDDDDDD"
+	Address     int    `hubman:"address"` // [1:512]
+}
+
+// Function returns string code of command
+func (r RDMSetStartAddress) Code() string {
+	return "RDMSetStartAddress"
+}
+
+// Function returns string description of command
+func (r RDMSetStartAddress) Description() string {
+	return "Sets DMX start address of RDM fixture connected to single DMX device"
+}
+
+// Represenation of RDM identify command
+type RDMIdentify struct {
+	DeviceAlias string `hubman:"device_alias"`
+	UID         string `hubman:"uid"` // "MMMM:DDDDDDDD"
+	Identify    bool   `hubman:"identify"`
+}
+
+// Function returns string code of command
+func (r RDMIdentify) Code() string {
+	return "RDMIdentify"
+}
+
+// Function returns string description of command
+func (r RDMIdentify) Description() string {
+	return "Switches identify mode of RDM fixture connected to single DMX device"
+}
+
+// Represenation of start effect command
+type StartEffect struct {
+	DeviceAlias string `hubman:"device_alias"`
+	Channels    []int  `hubman:"channels"`  // scene channel IDs of current scene
+	Waveform    string `hubman:"waveform"`  // one of "sine", "square", "saw", "triangle", "random", "strobe"
+	Amplitude   int    `hubman:"amplitude"` // deviation from channel value, [-255, 255]
+	Offset      int    `hubman:"offset"`    // deviation from channel value, [-255, 255]
+	PeriodMs    int    `hubman:"period_ms"`
+	Phase       int    `hubman:"phase"` // in degrees
+}
+
+// Function returns string code of command
+func (s StartEffect) Code() string {
+	return "StartEffect"
+}
+
+// Function returns string description of command
+func (s StartEffect) Description() string {
+	return "Starts waveform effect on chosen channels of current scene of single DMX/Artnet device by alias"
+}
+
+// Represenation of stop effect command
+type StopEffect struct {
+	DeviceAlias string `hubman:"device_alias"`
+	Channels    []int  `hubman:"channels"` // scene channel IDs of current scene, all effects are stopped if empty
+}
+
+// Function returns string code of command
+func (s StopEffect) Code() string {
+	return "StopEffect"
+}
+
+// Function returns string description of command
+func (s StopEffect) Description() string {
+	return "Stops effects on chosen channels of current scene of single DMX/Artnet device by alias"
+}
+
+// Represenation of cue go command
+type CueGo struct {
+	CueListAlias string `hubman:"cue_list_alias"`
+}
+
+// Function returns string code of command
+func (c CueGo) Code() string {
+	return "CueGo"
+}
+
+// Function returns string description of command
+func (c CueGo) Description() string {
+	return "Starts next cue of cue list by alias"
+}
+
+// Represenation of cue back command
+type CueBack struct {
+	CueListAlias string `hubman:"cue_list_alias"`
+}
+
+// Function returns string code of command
+func (c CueBack) Code() string {
+	return "CueBack"
+}
+
+// Function returns string description of command
+func (c CueBack) Description() string {
+	return "Starts previous cue of cue list by alias"
+}
+
+// Represenation of cue goto command
+type CueGoto struct {
+	CueListAlias string `hubman:"cue_list_alias"`
+	CueIndex     int    `hubman:"cue_index"`
+}
+
+// Function returns string code of command
+func (c CueGoto) Code() string {
+	return "CueGoto"
+}
+
+// Function returns string description of command
+func (c CueGoto) Description() string {
+	return "Starts cue of cue list by alias and cue index"
+}
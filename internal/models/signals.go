@@ -29,4 +29,162 @@ func (s SceneSaved) Code() string {
 // Function returns string description of signal
 func (s SceneSaved) Description() string {
 	return "SceneSaved - signal represents event of successful scene save on a single DMX-compatible device"
-}
\ No newline at end of file
+}
+
+// Represenation of scene created signal
+type SceneCreated struct {
+	DeviceAlias string `hubman:"device_alias"`
+	SceneAlias  string `hubman:"scene_alias"`
+}
+
+// Function returns string code of signal
+func (s SceneCreated) Code() string {
+	return "SceneCreated"
+}
+
+// Function returns string description of signal
+func (s SceneCreated) Description() string {
+	return "SceneCreated - signal represents event of scene creation at runtime on a single DMX-compatible device"
+}
+
+// Represenation of scene renamed signal
+type SceneRenamed struct {
+	DeviceAlias   string `hubman:"device_alias"`
+	SceneAlias    string `hubman:"scene_alias"`
+	NewSceneAlias string `hubman:"new_scene_alias"`
+}
+
+// Function returns string code of signal
+func (s SceneRenamed) Code() string {
+	return "SceneRenamed"
+}
+
+// Function returns string description of signal
+func (s SceneRenamed) Description() string {
+	return "SceneRenamed - signal represents event of scene rename on a single DMX-compatible device"
+}
+
+// Represenation of scene deleted signal
+type SceneDeleted struct {
+	DeviceAlias string `hubman:"device_alias"`
+	SceneAlias  string `hubman:"scene_alias"`
+}
+
+// Function returns string code of signal
+func (s SceneDeleted) Code() string {
+	return "SceneDeleted"
+}
+
+// Function returns string description of signal
+func (s SceneDeleted) Description() string {
+	return "SceneDeleted - signal represents event of scene deletion on a single DMX-compatible device"
+}
+
+// Represenation of universe snapshot signal
+type UniverseSnapshot struct {
+	DeviceAlias string `hubman:"device_alias"`
+	SceneAlias  string `hubman:"scene_alias"`
+	Universe    string `hubman:"universe"` // base64 of 512 channel values sent to device
+	Connected   bool   `hubman:"connected"`
+}
+
+// Function returns string code of signal
+func (u UniverseSnapshot) Code() string {
+	return "UniverseSnapshot"
+}
+
+// Function returns string description of signal
+func (u UniverseSnapshot) Description() string {
+	return "UniverseSnapshot - signal represents frame sent to a single DMX-compatible device including fades, effects and input"
+}
+
+// Represenation of current scene signal
+type CurrentScene struct {
+	DeviceAlias string `hubman:"device_alias"`
+	SceneAlias  string `hubman:"scene_alias"` // empty if no scene is selected
+}
+
+// Function returns string code of signal
+func (c CurrentScene) Code() string {
+	return "CurrentScene"
+}
+
+// Function returns string description of signal
+func (c CurrentScene) Description() string {
+	return "CurrentScene - signal represents current scene of a single DMX-compatible device or device group"
+}
+
+// Represenation of scene list signal
+type SceneList struct {
+	DeviceAlias  string   `hubman:"device_alias"`
+	Scenes       []string `hubman:"scenes"`
+	CurrentScene string   `hubman:"current_scene"`
+}
+
+// Function returns string code of signal
+func (s SceneList) Code() string {
+	return "SceneList"
+}
+
+// Function returns string description of signal
+func (s SceneList) Description() string {
+	return "SceneList - signal represents scenes of a single DMX-compatible device or device group"
+}
+
+// Represenation of device info signal
+type DeviceInfo struct {
+	DeviceAlias     string `hubman:"device_alias"`
+	SerialNumber    string `hubman:"serial_number"`
+	FirmwareVersion string `hubman:"firmware_version"`
+	BreakTime       int    `hubman:"break_time"`   // us
+	MABTime         int    `hubman:"mab_time"`     // us
+	RefreshRate     int    `hubman:"refresh_rate"` // packets per second, 0 is maximum
+}
+
+// Function returns string code of signal
+func (d DeviceInfo) Code() string {
+	return "DeviceInfo"
+}
+
+// Function returns string description of signal
+func (d DeviceInfo) Description() string {
+	return "DeviceInfo - signal represents serial number, firmware version and timing parameters of a connected DMX widget"
+}
+
+// Represenation of RDM responder signal
+type RDMResponder struct {
+	DeviceAlias  string `hubman:"device_alias"`
+	UID          string `hubman:"uid"`
+	Manufacturer string `hubman:"manufacturer"`
+	Model        string `hubman:"model"`
+	StartAddress int    `hubman:"start_address"`
+	Footprint    int    `hubman:"footprint"`
+}
+
+// Function returns string code of signal
+func (r RDMResponder) Code() string {
+	return "RDMResponder"
+}
+
+// Function returns string description of signal
+func (r RDMResponder) Description() string {
+	return "RDMResponder - signal represents RDM fixture discovered or updated on a single DMX device"
+}
+
+// Represenation of cue changed signal
+type CueChanged struct {
+	CueListAlias string `hubman:"cue_list_alias"`
+	CueIndex     int    `hubman:"cue_index"`
+	DeviceAlias  string `hubman:"device_alias"`
+	SceneAlias   string `hubman:"scene_alias"`
+}
+
+// Function returns string code of signal
+func (c CueChanged) Code() string {
+	return "CueChanged"
+}
+
+// Function returns string description of signal
+func (c CueChanged) Description() string {
+	return "CueChanged - signal represents event of cue start in a cue list"
+}
@@ -0,0 +1,235 @@
+package rdm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const (
+	MaxDiscoveryRequests = 10000
+	MuteAttempts         = 3
+)
+
+// ControllerUID is source UID of requests, taken from ESTA prototyping manufacturer ID range
+var ControllerUID = UID{0x7F, 0xF0, 0x00, 0x00, 0x00, 0x01}
+
+// Representation of RDM transport, nil response is returned if no responder answered.
+// Broadcast requests are only written, responders never answer them.
+type Transport interface {
+	SendRDM(packet []byte) ([]byte, error)
+	SendRDMDiscovery(packet []byte) ([]byte, error)
+	BroadcastRDM(packet []byte) error
+}
+
+// Representation of discovered RDM responder entity
+type Responder struct {
+	UID          UID
+	Manufacturer string
+	Model        string
+	StartAddress int // 0 if responder has no footprint
+	Footprint    int
+}
+
+// Representation of RDM controller entity
+type Controller struct {
+	transport   Transport
+	uid         UID
+	transaction uint8
+}
+
+// Function initializes RDM controller entity
+func NewController(transport Transport) *Controller {
+	return &Controller{
+		transport: transport,
+		uid:       ControllerUID,
+	}
+}
+
+// Function runs full discovery (binary search over UID space) and returns UIDs of muted responders
+func (c *Controller) Discover() ([]UID, error) {
+	err := c.broadcast(CommandDiscovery, PIDDiscUnMute, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[UID]struct{})
+	var uids []UID
+	branches := [][2]uint64{{MinUID.Uint64(), MaxUID.Uint64()}}
+	for requests := 0; len(branches) > 0; requests++ {
+		if requests >= MaxDiscoveryRequests {
+			return uids, fmt.Errorf("RDM discovery request limit is exceeded")
+		}
+
+		branch := branches[len(branches)-1]
+		branches = branches[:len(branches)-1]
+
+		response, err := c.uniqueBranch(UIDFromUint64(branch[0]), UIDFromUint64(branch[1]))
+		if err != nil {
+			return uids, err
+		}
+		if response == nil {
+			continue
+		}
+
+		uid, ok := DecodeDiscoveryResponse(response)
+		if ok {
+			if _, has := found[uid]; !has && c.mute(uid) {
+				found[uid] = struct{}{}
+				uids = append(uids, uid)
+				branches = append(branches, branch)
+				continue
+			}
+		}
+
+		if branch[0] == branch[1] {
+			continue
+		}
+		middle := branch[0] + (branch[1]-branch[0])/2
+		branches = append(branches, [2]uint64{middle + 1, branch[1]}, [2]uint64{branch[0], middle})
+	}
+	return uids, nil
+}
+
+// Function reads device info and labels of responder, missing labels are replaced with IDs
+func (c *Controller) Describe(uid UID) (Responder, error) {
+	data, err := c.Get(uid, PIDDeviceInfo, nil)
+	if err != nil {
+		return Responder{}, err
+	}
+	info, err := ParseDeviceInfo(data)
+	if err != nil {
+		return Responder{}, err
+	}
+
+	if info.StartAddress == 0xFFFF {
+		info.StartAddress = 0
+	}
+
+	responder := Responder{
+		UID:          uid,
+		Manufacturer: fmt.Sprintf("0x%04X", uid.ManufacturerID()),
+		Model:        fmt.Sprintf("0x%04X", info.ModelID),
+		StartAddress: info.StartAddress,
+		Footprint:    info.Footprint,
+	}
+	label, err := c.Get(uid, PIDManufacturerLabel, nil)
+	if err == nil && len(label) > 0 {
+		responder.Manufacturer = strings.TrimRight(string(label), "\x00")
+	}
+	label, err = c.Get(uid, PIDDeviceModelDescription, nil)
+	if err == nil && len(label) > 0 {
+		responder.Model = strings.TrimRight(string(label), "\x00")
+	}
+	return responder, nil
+}
+
+// Function sets DMX start address [1:512] of responder
+func (c *Controller) SetStartAddress(uid UID, address int) error {
+	if address < 1 || address > 512 {
+		return fmt.Errorf("DMX start address '%d' out of range [1, 512]", address)
+	}
+	return c.Set(uid, PIDDMXStartAddress, binary.BigEndian.AppendUint16(nil, uint16(address)))
+}
+
+// Function switches identify mode of responder
+func (c *Controller) Identify(uid UID, on bool) error {
+	value := byte(0)
+	if on {
+		value = 1
+	}
+	return c.Set(uid, PIDIdentifyDevice, []byte{value})
+}
+
+// Function sends GET request to responder and returns parameter data of acknowledged response
+func (c *Controller) Get(uid UID, pid uint16, data []byte) ([]byte, error) {
+	return c.request(uid, CommandGet, pid, data)
+}
+
+// Function sends SET request to responder and waits for acknowledged response
+func (c *Controller) Set(uid UID, pid uint16, data []byte) error {
+	_, err := c.request(uid, CommandSet, pid, data)
+	return err
+}
+
+// Function sends request to responder and validates its response
+func (c *Controller) request(uid UID, commandClass uint8, pid uint16, data []byte) ([]byte, error) {
+	packet, err := c.message(uid, commandClass, pid, data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.transport.SendRDM(packet)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("no RDM response from %s", uid)
+	}
+
+	response := Message{}
+	err = response.UnmarshalBinary(raw)
+	if err != nil {
+		return nil, err
+	}
+	if response.Source != uid || response.PID != pid || response.CommandClass != commandClass+1 || response.Transaction != c.transaction {
+		return nil, fmt.Errorf("unexpected RDM response from %s", response.Source)
+	}
+
+	switch response.PortID {
+	case ResponseAck:
+		return response.ParameterData, nil
+	case ResponseNack:
+		reason := uint16(0)
+		if len(response.ParameterData) >= 2 {
+			reason = binary.BigEndian.Uint16(response.ParameterData)
+		}
+		return nil, fmt.Errorf("RDM request to %s is not acknowledged, reason 0x%04X", uid, reason)
+	default:
+		return nil, fmt.Errorf("RDM response type 0x%02X from %s is not supported", response.PortID, uid)
+	}
+}
+
+// Function sends DISC_UNIQUE_BRANCH request and returns raw discovery response
+func (c *Controller) uniqueBranch(lower UID, upper UID) ([]byte, error) {
+	data := append(append([]byte{}, lower[:]...), upper[:]...)
+	packet, err := c.message(BroadcastUID, CommandDiscovery, PIDDiscUniqueBranch, data)
+	if err != nil {
+		return nil, err
+	}
+	return c.transport.SendRDMDiscovery(packet)
+}
+
+// Function mutes discovered responder, returns false if responder didn't acknowledge mute
+func (c *Controller) mute(uid UID) bool {
+	for i := 0; i < MuteAttempts; i++ {
+		_, err := c.request(uid, CommandDiscovery, PIDDiscMute, nil)
+		if err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Function sends broadcast request without response
+func (c *Controller) broadcast(commandClass uint8, pid uint16, data []byte) error {
+	packet, err := c.message(BroadcastUID, commandClass, pid, data)
+	if err != nil {
+		return err
+	}
+	return c.transport.BroadcastRDM(packet)
+}
+
+// Function builds request message with next transaction number
+func (c *Controller) message(uid UID, commandClass uint8, pid uint16, data []byte) ([]byte, error) {
+	c.transaction++
+	m := Message{
+		Destination:   uid,
+		Source:        c.uid,
+		Transaction:   c.transaction,
+		PortID:        1,
+		CommandClass:  commandClass,
+		PID:           pid,
+		ParameterData: data,
+	}
+	return m.MarshalBinary()
+}
@@ -0,0 +1,327 @@
+package rdm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// NACK reason codes of simulated responders
+const (
+	nackUnknownPID     = 0x0000
+	nackFormatError    = 0x0001
+	nackDataOutOfRange = 0x0006
+)
+
+// Representation of simulated RDM responder parameters
+type fakeResponder struct {
+	muted        bool
+	manufacturer string
+	model        string
+	modelID      uint16
+	footprint    int
+	startAddress int
+	identify     bool
+}
+
+// Representation of RDM line with simulated responders, colliding discovery responses are OR-ed together
+type fakeTransport struct {
+	t          *testing.T
+	responders map[UID]*fakeResponder
+	requests   int
+	broadcasts int
+}
+
+func newFakeTransport(t *testing.T, uids ...UID) *fakeTransport {
+	transport := &fakeTransport{t: t, responders: make(map[UID]*fakeResponder)}
+	for _, uid := range uids {
+		transport.responders[uid] = &fakeResponder{footprint: 4, startAddress: 1}
+	}
+	return transport
+}
+
+func (f *fakeTransport) decode(packet []byte) Message {
+	m := Message{}
+	err := m.UnmarshalBinary(packet)
+	if err != nil {
+		f.t.Fatalf("controller sent invalid RDM message: %v", err)
+	}
+	return m
+}
+
+func (f *fakeTransport) SendRDM(packet []byte) ([]byte, error) {
+	m := f.decode(packet)
+	if m.Destination == BroadcastUID {
+		f.t.Fatalf("broadcast PID 0x%04X is sent as request waiting for reply", m.PID)
+	}
+
+	responder, ok := f.responders[m.Destination]
+	if !ok {
+		return nil, nil
+	}
+	response := Message{
+		Destination:  m.Source,
+		Source:       m.Destination,
+		Transaction:  m.Transaction,
+		PortID:       ResponseAck,
+		CommandClass: m.CommandClass + 1,
+		PID:          m.PID,
+	}
+	response.PortID, response.ParameterData = responder.handle(m)
+	return response.MarshalBinary()
+}
+
+// Function handles request to simulated responder and returns response type and parameter data
+func (r *fakeResponder) handle(m Message) (uint8, []byte) {
+	nack := func(reason uint16) (uint8, []byte) {
+		return ResponseNack, binary.BigEndian.AppendUint16(nil, reason)
+	}
+
+	switch {
+	case m.CommandClass == CommandDiscovery && m.PID == PIDDiscMute:
+		r.muted = true
+		return ResponseAck, []byte{0, 0}
+	case m.CommandClass == CommandGet && m.PID == PIDDeviceInfo:
+		info := make([]byte, deviceInfoSize)
+		binary.BigEndian.PutUint16(info[0:2], 0x0100)
+		binary.BigEndian.PutUint16(info[2:4], r.modelID)
+		binary.BigEndian.PutUint16(info[10:12], uint16(r.footprint))
+		startAddress := r.startAddress
+		if r.footprint == 0 {
+			startAddress = 0xFFFF
+		}
+		binary.BigEndian.PutUint16(info[14:16], uint16(startAddress))
+		return ResponseAck, info
+	case m.CommandClass == CommandGet && m.PID == PIDManufacturerLabel && r.manufacturer != "":
+		return ResponseAck, []byte(r.manufacturer)
+	case m.CommandClass == CommandGet && m.PID == PIDDeviceModelDescription && r.model != "":
+		return ResponseAck, []byte(r.model)
+	case m.CommandClass == CommandSet && m.PID == PIDDMXStartAddress:
+		if len(m.ParameterData) != 2 {
+			return nack(nackFormatError)
+		}
+		address := int(binary.BigEndian.Uint16(m.ParameterData))
+		if r.footprint == 0 || address+r.footprint-1 > 512 {
+			return nack(nackDataOutOfRange)
+		}
+		r.startAddress = address
+		return ResponseAck, nil
+	case m.CommandClass == CommandSet && m.PID == PIDIdentifyDevice:
+		if len(m.ParameterData) != 1 || m.ParameterData[0] > 1 {
+			return nack(nackFormatError)
+		}
+		r.identify = m.ParameterData[0] == 1
+		return ResponseAck, nil
+	default:
+		return nack(nackUnknownPID)
+	}
+}
+
+func (f *fakeTransport) SendRDMDiscovery(packet []byte) ([]byte, error) {
+	f.requests++
+	m := f.decode(packet)
+	if m.PID != PIDDiscUniqueBranch || len(m.ParameterData) != 12 {
+		f.t.Fatalf("invalid discovery request PID 0x%04X", m.PID)
+	}
+	var lower, upper UID
+	copy(lower[:], m.ParameterData[:6])
+	copy(upper[:], m.ParameterData[6:])
+
+	var response []byte
+	for uid, responder := range f.responders {
+		if responder.muted || uid.Uint64() < lower.Uint64() || uid.Uint64() > upper.Uint64() {
+			continue
+		}
+		encoded := EncodeDiscoveryResponse(uid)
+		if response == nil {
+			response = encoded
+			continue
+		}
+		for i := range response {
+			response[i] |= encoded[i]
+		}
+	}
+	return response, nil
+}
+
+func (f *fakeTransport) BroadcastRDM(packet []byte) error {
+	m := f.decode(packet)
+	if m.Destination != BroadcastUID {
+		f.t.Fatalf("request to %s is sent as broadcast", m.Destination)
+	}
+	f.broadcasts++
+	if m.PID == PIDDiscUnMute {
+		for _, responder := range f.responders {
+			responder.muted = false
+		}
+	}
+	return nil
+}
+
+func TestDiscover(t *testing.T) {
+	tests := []struct {
+		name string
+		uids []UID
+	}{
+		{name: "no responders"},
+		{name: "single responder", uids: []UID{{0x45, 0x4E, 0x12, 0x34, 0x56, 0x78}}},
+		{
+			name: "colliding responders of one manufacturer",
+			uids: []UID{
+				{0x45, 0x4E, 0x00, 0x00, 0x00, 0x01},
+				{0x45, 0x4E, 0x00, 0x00, 0x00, 0x02},
+				{0x45, 0x4E, 0x00, 0x00, 0x00, 0x03},
+				{0x45, 0x4E, 0x80, 0x00, 0x00, 0x00},
+			},
+		},
+		{
+			name: "responders across UID space",
+			uids: []UID{
+				{0x00, 0x01, 0x00, 0x00, 0x00, 0x00},
+				{0x02, 0x7B, 0xDE, 0xAD, 0xBE, 0xEF},
+				{0x45, 0x4E, 0xFF, 0xFF, 0xFF, 0xFE},
+				{0x7F, 0xF0, 0x00, 0x00, 0x10, 0x00},
+				{0x7F, 0xF0, 0x00, 0x00, 0x10, 0x01},
+				{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := newFakeTransport(t, tt.uids...)
+			controller := NewController(transport)
+
+			uids, err := controller.Discover()
+			if err != nil {
+				t.Fatalf("Discover() error = %v", err)
+			}
+			if transport.requests > MaxDiscoveryRequests {
+				t.Errorf("discovery took %d requests, limit is %d", transport.requests, MaxDiscoveryRequests)
+			}
+			if transport.broadcasts != 1 {
+				t.Errorf("responders were un-muted %d times, want 1", transport.broadcasts)
+			}
+
+			want := make([]string, 0, len(tt.uids))
+			for _, uid := range tt.uids {
+				want = append(want, uid.String())
+			}
+			got := make([]string, 0, len(uids))
+			for _, uid := range uids {
+				got = append(got, uid.String())
+			}
+			sort.Strings(want)
+			sort.Strings(got)
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("Discover() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	labeled := UID{0x45, 0x4E, 0x00, 0x00, 0x00, 0x01}
+	unlabeled := UID{0x45, 0x4E, 0x00, 0x00, 0x00, 0x02}
+	noFootprint := UID{0x45, 0x4E, 0x00, 0x00, 0x00, 0x03}
+	transport := newFakeTransport(t, labeled, unlabeled, noFootprint)
+	*transport.responders[labeled] = fakeResponder{manufacturer: "Acme\x00", model: "PAR 64", modelID: 0x0102, footprint: 7, startAddress: 33}
+	*transport.responders[unlabeled] = fakeResponder{modelID: 0x0A0B, footprint: 1, startAddress: 512}
+	*transport.responders[noFootprint] = fakeResponder{modelID: 0x0001}
+
+	tests := []struct {
+		uid  UID
+		want Responder
+	}{
+		{uid: labeled, want: Responder{UID: labeled, Manufacturer: "Acme", Model: "PAR 64", StartAddress: 33, Footprint: 7}},
+		{uid: unlabeled, want: Responder{UID: unlabeled, Manufacturer: "0x454E", Model: "0x0A0B", StartAddress: 512, Footprint: 1}},
+		{uid: noFootprint, want: Responder{UID: noFootprint, Manufacturer: "0x454E", Model: "0x0001"}},
+	}
+
+	controller := NewController(transport)
+	for _, tt := range tests {
+		got, err := controller.Describe(tt.uid)
+		if err != nil {
+			t.Fatalf("Describe(%s) error = %v", tt.uid, err)
+		}
+		if got != tt.want {
+			t.Errorf("Describe(%s) = %+v, want %+v", tt.uid, got, tt.want)
+		}
+	}
+
+	_, err := controller.Describe(UID{0x45, 0x4E, 0x00, 0x00, 0x00, 0x04})
+	if err == nil {
+		t.Errorf("Describe() of missing responder succeeded")
+	}
+}
+
+func TestSetStartAddress(t *testing.T) {
+	uid := UID{0x45, 0x4E, 0x12, 0x34, 0x56, 0x78}
+	transport := newFakeTransport(t, uid)
+	controller := NewController(transport)
+
+	tests := []struct {
+		name    string
+		address int
+		want    int
+		wantErr bool
+	}{
+		{name: "acknowledged", address: 100, want: 100},
+		{name: "last address fitting footprint", address: 509, want: 509},
+		{name: "not acknowledged by responder", address: 510, want: 509, wantErr: true},
+		{name: "out of DMX range", address: 0, want: 509, wantErr: true},
+		{name: "out of DMX range above", address: 513, want: 509, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := controller.SetStartAddress(uid, tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetStartAddress(%d) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+			}
+			if got := transport.responders[uid].startAddress; got != tt.want {
+				t.Errorf("start address of responder = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	responder, err := controller.Describe(uid)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if responder.StartAddress != 509 {
+		t.Errorf("described start address = %d, want 509", responder.StartAddress)
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	uid := UID{0x45, 0x4E, 0x12, 0x34, 0x56, 0x78}
+	transport := newFakeTransport(t, uid)
+	controller := NewController(transport)
+
+	for _, on := range []bool{true, false, true} {
+		err := controller.Identify(uid, on)
+		if err != nil {
+			t.Fatalf("Identify(%v) error = %v", on, err)
+		}
+		if transport.responders[uid].identify != on {
+			t.Errorf("identify mode of responder = %v, want %v", transport.responders[uid].identify, on)
+		}
+	}
+
+	err := controller.Identify(UID{0x45, 0x4E, 0x00, 0x00, 0x00, 0x01}, true)
+	if err == nil {
+		t.Errorf("Identify() of missing responder succeeded")
+	}
+}
+
+func TestRequestNotAcknowledged(t *testing.T) {
+	uid := UID{0x45, 0x4E, 0x12, 0x34, 0x56, 0x78}
+	controller := NewController(newFakeTransport(t, uid))
+
+	_, err := controller.Get(uid, 0x8000, nil)
+	if err == nil || !strings.Contains(err.Error(), "reason 0x0000") {
+		t.Errorf("Get() of unknown PID error = %v, want NACK with reason 0x0000", err)
+	}
+}
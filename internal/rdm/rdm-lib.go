@@ -0,0 +1,236 @@
+package rdm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	StartCode    = 0xCC
+	SubStartCode = 0x01
+	HeaderSize   = 24
+
+	CommandDiscovery         = 0x10
+	CommandDiscoveryResponse = 0x11
+	CommandGet               = 0x20
+	CommandGetResponse       = 0x21
+	CommandSet               = 0x30
+	CommandSetResponse       = 0x31
+
+	ResponseAck         = 0x00
+	ResponseAckTimer    = 0x01
+	ResponseNack        = 0x02
+	ResponseAckOverflow = 0x03
+
+	PIDDiscUniqueBranch       = 0x0001
+	PIDDiscMute               = 0x0002
+	PIDDiscUnMute             = 0x0003
+	PIDDeviceInfo             = 0x0060
+	PIDDeviceModelDescription = 0x0080
+	PIDManufacturerLabel      = 0x0081
+	PIDDMXStartAddress        = 0x00F0
+	PIDIdentifyDevice         = 0x1000
+
+	discoveryPreamble  = 0xFE
+	discoverySeparator = 0xAA
+	deviceInfoSize     = 19
+)
+
+// Representation of RDM unique identifier (manufacturer ID and device ID)
+type UID [6]byte
+
+var (
+	BroadcastUID = UID{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	MinUID       = UID{0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	MaxUID       = UID{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE}
+)
+
+// Function returns UID in "MMMM:DDDDDDDD" format
+func (u UID) String() string {
+	return fmt.Sprintf("%04X:%08X", binary.BigEndian.Uint16(u[0:2]), binary.BigEndian.Uint32(u[2:6]))
+}
+
+// Function returns manufacturer ID of UID
+func (u UID) ManufacturerID() uint16 {
+	return binary.BigEndian.Uint16(u[0:2])
+}
+
+// Function returns UID as 48-bit number
+func (u UID) Uint64() uint64 {
+	var b [8]byte
+	copy(b[2:], u[:])
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Function returns UID from 48-bit number
+func UIDFromUint64(value uint64) UID {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], value)
+	var u UID
+	copy(u[:], b[2:])
+	return u
+}
+
+// Function parses UID in "MMMM:DDDDDDDD" format
+func ParseUID(s string) (UID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || len(parts[0]) != 4 || len(parts[1]) != 8 {
+		return UID{}, fmt.Errorf("invalid RDM UID '%s' (expected 'MMMM:DDDDDDDD')", s)
+	}
+	manufacturer, err := strconv.ParseUint(parts[0], 16, 16)
+	if err != nil {
+		return UID{}, fmt.Errorf("invalid RDM UID '%s': %v", s, err)
+	}
+	device, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return UID{}, fmt.Errorf("invalid RDM UID '%s': %v", s, err)
+	}
+
+	var u UID
+	binary.BigEndian.PutUint16(u[0:2], uint16(manufacturer))
+	binary.BigEndian.PutUint32(u[2:6], uint32(device))
+	return u, nil
+}
+
+// Representation of RDM message entity
+type Message struct {
+	Destination   UID
+	Source        UID
+	Transaction   uint8
+	PortID        uint8 // response type in responses
+	MessageCount  uint8
+	SubDevice     uint16
+	CommandClass  uint8
+	PID           uint16
+	ParameterData []byte
+}
+
+// Function marshals RDM message with start code and checksum
+func (m *Message) MarshalBinary() ([]byte, error) {
+	if len(m.ParameterData) > 231 {
+		return nil, fmt.Errorf("RDM parameter data is too long")
+	}
+
+	b := make([]byte, HeaderSize, HeaderSize+len(m.ParameterData)+2)
+	b[0] = StartCode
+	b[1] = SubStartCode
+	b[2] = byte(HeaderSize + len(m.ParameterData))
+	copy(b[3:9], m.Destination[:])
+	copy(b[9:15], m.Source[:])
+	b[15] = m.Transaction
+	b[16] = m.PortID
+	b[17] = m.MessageCount
+	binary.BigEndian.PutUint16(b[18:20], m.SubDevice)
+	b[20] = m.CommandClass
+	binary.BigEndian.PutUint16(b[21:23], m.PID)
+	b[23] = byte(len(m.ParameterData))
+	b = append(b, m.ParameterData...)
+	return binary.BigEndian.AppendUint16(b, checksum(b)), nil
+}
+
+// Function unmarshals RDM message starting with start code and validates its checksum
+func (m *Message) UnmarshalBinary(b []byte) error {
+	if len(b) < HeaderSize+2 || b[0] != StartCode || b[1] != SubStartCode {
+		return fmt.Errorf("invalid RDM message")
+	}
+	size := int(b[2])
+	if size < HeaderSize || len(b) < size+2 || int(b[23]) != size-HeaderSize {
+		return fmt.Errorf("invalid RDM message length")
+	}
+	if checksum(b[:size]) != binary.BigEndian.Uint16(b[size:size+2]) {
+		return fmt.Errorf("invalid RDM message checksum")
+	}
+
+	copy(m.Destination[:], b[3:9])
+	copy(m.Source[:], b[9:15])
+	m.Transaction = b[15]
+	m.PortID = b[16]
+	m.MessageCount = b[17]
+	m.SubDevice = binary.BigEndian.Uint16(b[18:20])
+	m.CommandClass = b[20]
+	m.PID = binary.BigEndian.Uint16(b[21:23])
+	m.ParameterData = append([]byte(nil), b[HeaderSize:size]...)
+	return nil
+}
+
+// Function decodes discovery unique branch response (preamble, separator, encoded UID and checksum),
+// returns false for collisions and corrupted responses
+func DecodeDiscoveryResponse(b []byte) (UID, bool) {
+	i := 0
+	for i < len(b) && i < 7 && b[i] == discoveryPreamble {
+		i++
+	}
+	if i >= len(b) || b[i] != discoverySeparator {
+		return UID{}, false
+	}
+	b = b[i+1:]
+	if len(b) < 16 {
+		return UID{}, false
+	}
+
+	var u UID
+	sum := uint16(0)
+	for j := 0; j < 6; j++ {
+		u[j] = b[2*j] & b[2*j+1]
+		sum += uint16(b[2*j]) + uint16(b[2*j+1])
+	}
+	expected := uint16(b[12]&b[13])<<8 | uint16(b[14]&b[15])
+	if sum != expected {
+		return UID{}, false
+	}
+	return u, true
+}
+
+// Function encodes discovery unique branch response of UID
+func EncodeDiscoveryResponse(u UID) []byte {
+	b := []byte{discoveryPreamble, discoveryPreamble, discoveryPreamble, discoveryPreamble,
+		discoveryPreamble, discoveryPreamble, discoveryPreamble, discoverySeparator}
+	sum := uint16(0)
+	for _, v := range u {
+		b = append(b, v|0xAA, v|0x55)
+		sum += uint16(v|0xAA) + uint16(v|0x55)
+	}
+	return append(b, byte(sum>>8)|0xAA, byte(sum>>8)|0x55, byte(sum)|0xAA, byte(sum)|0x55)
+}
+
+// Representation of RDM device info entity (DEVICE_INFO parameter)
+type DeviceInfo struct {
+	ProtocolVersion uint16
+	ModelID         uint16
+	ProductCategory uint16
+	SoftwareVersion uint32
+	Footprint       int
+	Personality     uint16
+	StartAddress    int // 0xFFFF if device has no footprint
+	SubDeviceCount  int
+	SensorCount     int
+}
+
+// Function parses DEVICE_INFO parameter data
+func ParseDeviceInfo(b []byte) (DeviceInfo, error) {
+	if len(b) < deviceInfoSize {
+		return DeviceInfo{}, fmt.Errorf("RDM device info is too short")
+	}
+	return DeviceInfo{
+		ProtocolVersion: binary.BigEndian.Uint16(b[0:2]),
+		ModelID:         binary.BigEndian.Uint16(b[2:4]),
+		ProductCategory: binary.BigEndian.Uint16(b[4:6]),
+		SoftwareVersion: binary.BigEndian.Uint32(b[6:10]),
+		Footprint:       int(binary.BigEndian.Uint16(b[10:12])),
+		Personality:     binary.BigEndian.Uint16(b[12:14]),
+		StartAddress:    int(binary.BigEndian.Uint16(b[14:16])),
+		SubDeviceCount:  int(binary.BigEndian.Uint16(b[16:18])),
+		SensorCount:     int(b[18]),
+	}, nil
+}
+
+// Function returns RDM checksum (sum of all bytes)
+func checksum(b []byte) uint16 {
+	sum := uint16(0)
+	for _, v := range b {
+		sum += uint16(v)
+	}
+	return sum
+}
@@ -0,0 +1,184 @@
+package sacn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+
+	"go.uber.org/zap"
+)
+
+// Function initializes and returns sACN device entity
+func NewSACNDevice(ctx context.Context, signals chan core.Signal, conf device.SACNConfig, profiles []device.FixtureProfileConfig, logger *zap.Logger, checkManager core.CheckRegistry, persister *device.Persister) (device.Device, error) {
+	patch, err := device.ReadPatchFromDeviceConfig(profiles, conf.Fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	cid, err := ParseCID(conf.CID, conf.Alias)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := resolveDestination(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceName := conf.SourceName
+	if sourceName == "" {
+		sourceName = conf.Alias
+	}
+	priority := conf.Priority
+	if priority == 0 {
+		priority = DefaultPriority
+	}
+
+	newSACN := &sacnDevice{
+		BaseDevice:  *device.NewBaseDevice(ctx, conf.Alias, conf.NonBlackoutChannels, conf.Scenes, patch, conf.ReconnectInterval, conf.FrameRate, signals, logger, checkManager, persister),
+		destination: destination,
+		packet: Packet{
+			CID:        cid,
+			SourceName: sourceName,
+			Priority:   uint8(priority),
+			Universe:   uint16(conf.Universe),
+		},
+		conn: nil,
+	}
+	if conf.KeepAliveInterval <= 0 {
+		conf.KeepAliveInterval = device.DefaultSACNKeepAliveInterval
+	}
+	newSACN.KeepAliveInterval = time.Duration(conf.KeepAliveInterval) * time.Millisecond
+
+	newSACN.AllowSetUniverse = conf.AllowSetUniverse
+	newSACN.ProtectedChannels = device.ReadProtectedChannelsFromDeviceConfig(conf.ProtectedChannels)
+
+	go newSACN.reconnect()
+	go newSACN.RunOutput(newSACN.WriteFrameToDevice)
+	return newSACN, nil
+}
+
+type sacnDevice struct {
+	device.BaseDevice
+	destination *net.UDPAddr
+	packet      Packet
+	conn        *net.UDPConn
+	ioMutex     sync.Mutex // guards packet and conn, device mutex is not held during network I/O
+}
+
+// Function resolves unicast destination or multicast address of universe
+func resolveDestination(conf device.SACNConfig) (*net.UDPAddr, error) {
+	port := conf.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+
+	if conf.Destination == "" {
+		return &net.UDPAddr{IP: MulticastAddr(uint16(conf.Universe)), Port: port}, nil
+	}
+
+	ip := net.ParseIP(conf.Destination)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid sACN destination address '%s'", conf.Destination)
+	}
+	return &net.UDPAddr{IP: ip, Port: port}, nil
+}
+
+// Function reconnects single sACN device
+func (d *sacnDevice) reconnect() {
+	ticker := time.NewTicker(d.ReconnectInterval)
+	for {
+		select {
+		case <-d.StopReconnect:
+			ticker.Stop()
+			return
+		case <-ticker.C:
+			if !d.Connected.Load() {
+				d.connect()
+			}
+		}
+	}
+}
+
+// Function opens UDP socket to the sACN destination
+func (d *sacnDevice) connect() {
+	conn, err := net.DialUDP("udp", nil, d.destination)
+	if err != nil {
+		connCheck := core.NewCheck(
+			fmt.Sprintf(device.DeviceDisconnectedCheckLabelFormat, d.Alias),
+			"",
+		)
+		d.CheckManager.RegisterFail(connCheck)
+		d.Logger.Warn("Unable to connect sACN device", zap.Any("destination", d.destination.String()), zap.Error(err))
+		return
+	}
+
+	d.ioMutex.Lock()
+	d.conn = conn
+	d.ioMutex.Unlock()
+	d.Connected.CompareAndSwap(false, true)
+
+	connCheck := core.NewCheck(
+		fmt.Sprintf(device.DeviceDisconnectedCheckLabelFormat, d.Alias),
+		"",
+	)
+	d.CheckManager.RegisterSuccess(connCheck)
+	d.Logger.Info("Connected sACN device", zap.Any("destination", d.destination.String()), zap.Any("universe", d.packet.Universe))
+}
+
+// Function writes frame to single sACN device, failed write marks device as disconnected
+func (d *sacnDevice) WriteFrameToDevice(frame [512]byte) error {
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
+
+	if !d.Connected.Load() {
+		return fmt.Errorf("no connection to device")
+	}
+
+	d.packet.Data = frame
+	err := d.send()
+	if err != nil {
+		d.conn.Close()
+		d.Connected.CompareAndSwap(true, false)
+		return fmt.Errorf("sending frame to device error: %v", err)
+	}
+	return nil
+}
+
+// Function sends current packet with next sequence number, caller must hold I/O mutex
+func (d *sacnDevice) send() error {
+	d.packet.Sequence++
+	data, err := d.packet.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = d.conn.Write(data)
+	return err
+}
+
+// Function frees resources of sACN device entity, receivers are notified with stream terminated packets
+func (d *sacnDevice) Close() {
+	d.BaseDevice.Close()
+	if !d.Connected.Load() {
+		return
+	}
+
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
+
+	d.packet.Terminated = true
+	for i := 0; i < 3; i++ {
+		err := d.send()
+		if err != nil {
+			d.Logger.Debug("sending stream terminated packet failed", zap.Error(err))
+			break
+		}
+	}
+	d.conn.Close()
+}
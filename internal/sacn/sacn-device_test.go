@@ -0,0 +1,84 @@
+package sacn
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"git.miem.hse.ru/hubman/dmx-executor/internal/models"
+)
+
+func TestDeviceSendsFrameOverUDP(t *testing.T) {
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("loopback UDP is not available: %v", err)
+	}
+	defer listener.Close()
+
+	signals := make(chan core.Signal, 16)
+	persister := device.NewPersister(device.NewMemoryStore(), 0, zap.NewNop())
+	defer persister.Close()
+
+	conf := device.SACNConfig{
+		Alias:            "sacn",
+		Universe:         7,
+		Priority:         150,
+		SourceName:       "test source",
+		Destination:      "127.0.0.1",
+		Port:             listener.LocalAddr().(*net.UDPAddr).Port,
+		AllowSetUniverse: true,
+		FrameRate:        40,
+	}
+	dev, err := NewSACNDevice(context.Background(), signals, conf, nil, zap.NewNop(), core.NewCheckManager(), persister)
+	if err != nil {
+		t.Fatalf("NewSACNDevice() error = %v", err)
+	}
+	defer dev.Close()
+
+	// device connects on first reconnect tick
+	deadline := time.Now().Add(5 * time.Second)
+	for !dev.GetBaseDevice().Connected.Load() {
+		if time.Now().After(deadline) {
+			t.Fatalf("device is not connected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	err = dev.SetUniverse(context.Background(), models.SetUniverse{DeviceAlias: "sacn", Data: "AQID", Start: 10})
+	if err != nil {
+		t.Fatalf("SetUniverse() error = %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	for {
+		listener.SetReadDeadline(deadline)
+		n, _, err := listener.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatalf("no frame received: %v", err)
+		}
+		if n != PacketSize {
+			t.Fatalf("received %d bytes, want %d", n, PacketSize)
+		}
+		if buf[126+10] == 0 {
+			continue // frame sent before universe was set
+		}
+
+		if got := string(buf[44:55]); got != "test source" {
+			t.Errorf("source name = %q", got)
+		}
+		if buf[108] != 150 {
+			t.Errorf("priority = %d, want 150", buf[108])
+		}
+		if buf[113] != 0 || buf[114] != 7 {
+			t.Errorf("universe = %d, want 7", int(buf[113])<<8|int(buf[114]))
+		}
+		if got := buf[126+10 : 126+13]; got[0] != 1 || got[1] != 2 || got[2] != 3 {
+			t.Errorf("slots 10-12 = %v, want [1 2 3]", got)
+		}
+		return
+	}
+}
@@ -0,0 +1,98 @@
+package sacn
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	DefaultPort     = 5568
+	DefaultPriority = 100
+	PacketSize      = 638
+	SourceNameSize  = 64
+
+	vectorRootE131Data     = 0x00000004
+	vectorE131DataPacket   = 0x00000002
+	vectorDMPSetProperty   = 0x02
+	dmpAddressDataType     = 0xa1
+	optionStreamTerminated = 0x40
+)
+
+var acnPacketIdentifier = [12]byte{0x41, 0x53, 0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0x00, 0x00, 0x00}
+
+// Representation of E1.31 (sACN) data packet entity
+type Packet struct {
+	CID        [16]byte
+	SourceName string
+	Priority   uint8
+	Sequence   uint8
+	Terminated bool
+	Universe   uint16
+	Data       [512]byte
+}
+
+// Function marshals E1.31 data packet with full 512 slots frame
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	b := make([]byte, PacketSize)
+
+	// Root layer
+	binary.BigEndian.PutUint16(b[0:2], 0x0010)
+	binary.BigEndian.PutUint16(b[2:4], 0x0000)
+	copy(b[4:16], acnPacketIdentifier[:])
+	binary.BigEndian.PutUint16(b[16:18], 0x7000|uint16(PacketSize-16))
+	binary.BigEndian.PutUint32(b[18:22], vectorRootE131Data)
+	copy(b[22:38], p.CID[:])
+
+	// Framing layer
+	binary.BigEndian.PutUint16(b[38:40], 0x7000|uint16(PacketSize-38))
+	binary.BigEndian.PutUint32(b[40:44], vectorE131DataPacket)
+	copy(b[44:44+SourceNameSize-1], p.SourceName)
+	b[108] = p.Priority
+	binary.BigEndian.PutUint16(b[109:111], 0)
+	b[111] = p.Sequence
+	if p.Terminated {
+		b[112] = optionStreamTerminated
+	}
+	binary.BigEndian.PutUint16(b[113:115], p.Universe)
+
+	// DMP layer
+	binary.BigEndian.PutUint16(b[115:117], 0x7000|uint16(PacketSize-115))
+	b[117] = vectorDMPSetProperty
+	b[118] = dmpAddressDataType
+	binary.BigEndian.PutUint16(b[119:121], 0x0000)
+	binary.BigEndian.PutUint16(b[121:123], 0x0001)
+	binary.BigEndian.PutUint16(b[123:125], 513)
+	b[125] = 0
+	copy(b[126:], p.Data[:])
+
+	return b, nil
+}
+
+// Function returns multicast address of sACN universe
+func MulticastAddr(universe uint16) net.IP {
+	return net.IPv4(239, 255, byte(universe>>8), byte(universe))
+}
+
+// Function parses CID in UUID form, empty CID is derived from seed (device alias) to stay stable between restarts
+func ParseCID(cid string, seed string) ([16]byte, error) {
+	var result [16]byte
+
+	if cid == "" {
+		sum := sha1.Sum([]byte("dmx-executor/sacn/" + seed))
+		copy(result[:], sum[:16])
+		result[6] = result[6]&0x0f | 0x50
+		result[8] = result[8]&0x3f | 0x80
+		return result, nil
+	}
+
+	raw, err := hex.DecodeString(strings.ReplaceAll(cid, "-", ""))
+	if err != nil || len(raw) != 16 {
+		return result, fmt.Errorf("invalid sACN CID '%s', UUID expected", cid)
+	}
+	copy(result[:], raw)
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package sacn
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// Function builds expected E1.31 packet byte by byte from the layout of ANSI E1.31-2018 table 4-1
+func goldenPacket(cid [16]byte, sourceName string, priority byte, sequence byte, options byte, universe uint16, data [512]byte) []byte {
+	var b bytes.Buffer
+
+	// Root layer
+	b.Write([]byte{0x00, 0x10, 0x00, 0x00})
+	b.WriteString("ASC-E1.17\x00\x00\x00")
+	b.Write([]byte{0x72, 0x6e}) // flags and length 622
+	b.Write([]byte{0x00, 0x00, 0x00, 0x04})
+	b.Write(cid[:])
+
+	// Framing layer
+	b.Write([]byte{0x72, 0x58}) // flags and length 600
+	b.Write([]byte{0x00, 0x00, 0x00, 0x02})
+	name := make([]byte, 64)
+	copy(name[:63], sourceName)
+	b.Write(name)
+	b.Write([]byte{priority, 0x00, 0x00, sequence, options, byte(universe >> 8), byte(universe)})
+
+	// DMP layer
+	b.Write([]byte{0x72, 0x0b}) // flags and length 523
+	b.Write([]byte{0x02, 0xa1, 0x00, 0x00, 0x00, 0x01, 0x02, 0x01, 0x00})
+	b.Write(data[:])
+	return b.Bytes()
+}
+
+func TestPacketMarshalBinary(t *testing.T) {
+	var cid [16]byte
+	raw, _ := hex.DecodeString("5e2e5a3c8b5d4c1f9a7e0123456789ab")
+	copy(cid[:], raw)
+
+	var data [512]byte
+	data[0] = 0xff
+	data[255] = 0x80
+	data[511] = 0x01
+
+	tests := []struct {
+		name    string
+		packet  Packet
+		options byte
+	}{
+		{
+			name:   "data",
+			packet: Packet{CID: cid, SourceName: "stage left", Priority: 100, Sequence: 7, Universe: 1, Data: data},
+		},
+		{
+			name:    "terminated",
+			packet:  Packet{CID: cid, SourceName: "stage left", Priority: 200, Sequence: 255, Terminated: true, Universe: 63999},
+			options: optionStreamTerminated,
+		},
+		{
+			name:   "long source name is truncated to 63 bytes",
+			packet: Packet{CID: cid, SourceName: strings.Repeat("x", 80), Priority: 0, Sequence: 0, Universe: 256, Data: data},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.packet.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			want := goldenPacket(cid, tt.packet.SourceName, tt.packet.Priority, tt.packet.Sequence, tt.options, tt.packet.Universe, tt.packet.Data)
+			if len(got) != PacketSize || len(want) != PacketSize {
+				t.Fatalf("packet size = %d, golden size = %d, want %d", len(got), len(want), PacketSize)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("byte %d = %#02x, want %#02x", i, got[i], want[i])
+				}
+			}
+			if got[107] != 0 {
+				t.Errorf("source name is not null-terminated")
+			}
+		})
+	}
+}
+
+func TestMulticastAddr(t *testing.T) {
+	tests := []struct {
+		universe uint16
+		want     string
+	}{
+		{1, "239.255.0.1"},
+		{256, "239.255.1.0"},
+		{63999, "239.255.249.255"},
+	}
+
+	for _, tt := range tests {
+		if got := MulticastAddr(tt.universe).String(); got != tt.want {
+			t.Errorf("MulticastAddr(%d) = %s, want %s", tt.universe, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package virtual
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+
+	"go.uber.org/zap"
+)
+
+// Minimal interval between frames dumped to log, changes in between are logged with next dumped frame
+const logDumpInterval = time.Second
+
+// Function initializes and returns virtual device entity
+func NewVirtualDevice(ctx context.Context, signals chan core.Signal, conf device.VirtualConfig, profiles []device.FixtureProfileConfig, logger *zap.Logger, checkManager core.CheckRegistry, persister *device.Persister) (device.Device, error) {
+	patch, err := device.ReadPatchFromDeviceConfig(profiles, conf.Fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	var file *os.File
+	if conf.Dump == device.VirtualDumpFile {
+		file, err = os.OpenFile(conf.DumpPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("opening dump file error: %v", err)
+		}
+	}
+
+	newVirtual := &virtualDevice{
+		BaseDevice: *device.NewBaseDevice(ctx, conf.Alias, conf.NonBlackoutChannels, conf.Scenes, patch, conf.ReconnectInterval, conf.FrameRate, signals, logger, checkManager, persister),
+		dump:       conf.Dump,
+		file:       file,
+		frame:      [512]byte{},
+		frameCount: 0,
+	}
+	newVirtual.Connected.Store(true)
+
+	connCheck := core.NewCheck(
+		fmt.Sprintf(device.DeviceDisconnectedCheckLabelFormat, newVirtual.Alias),
+		"",
+	)
+	newVirtual.CheckManager.RegisterSuccess(connCheck)
+
+	newVirtual.AllowSetUniverse = conf.AllowSetUniverse
+	newVirtual.ProtectedChannels = device.ReadProtectedChannelsFromDeviceConfig(conf.ProtectedChannels)
+
+	go newVirtual.reconnect()
+	go newVirtual.RunOutput(newVirtual.WriteFrameToDevice)
+	return newVirtual, nil
+}
+
+// Representation of virtual device entity, always connected, sent frame is available with GetUniverse
+type virtualDevice struct {
+	device.BaseDevice
+	dump       string
+	file       *os.File
+	frame      [512]byte // last sent frame, changed frames are dumped
+	frameCount uint64
+	loggedAt   time.Time
+	logPending bool       // changed frame is not logged yet
+	ioMutex    sync.Mutex // guards dump file and last sent frame, device mutex is not held while dumping
+}
+
+// Function keeps virtual device connected until it is closed
+func (d *virtualDevice) reconnect() {
+	<-d.StopReconnect
+}
+
+// Function dumps changed frames of virtual device to file or log, log dump is rate-limited
+func (d *virtualDevice) WriteFrameToDevice(frame [512]byte) error {
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
+
+	changed := d.frameCount == 0 || frame != d.frame
+	d.frame = frame
+	d.frameCount++
+
+	switch d.dump {
+	case device.VirtualDumpLog:
+		d.logPending = d.logPending || changed
+		now := time.Now()
+		if !d.logPending || now.Sub(d.loggedAt) < logDumpInterval {
+			return nil
+		}
+		d.logPending = false
+		d.loggedAt = now
+		d.Logger.Info("virtual device frame", zap.Uint64("frame", d.frameCount), zap.String("universe", hex.EncodeToString(frame[:])))
+	case device.VirtualDumpFile:
+		if !changed {
+			return nil
+		}
+		_, err := fmt.Fprintf(d.file, "%s %s\n", time.Now().Format(time.RFC3339Nano), hex.EncodeToString(frame[:]))
+		if err != nil {
+			return fmt.Errorf("writing frame to dump file error: %v", err)
+		}
+	}
+	return nil
+}
+
+// Function frees resources of virtual device entity
+func (d *virtualDevice) Close() {
+	d.BaseDevice.Close()
+	d.ioMutex.Lock()
+	defer d.ioMutex.Unlock()
+	if d.file != nil {
+		d.file.Close()
+	}
+}
@@ -0,0 +1,44 @@
+package virtual
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"git.miem.hse.ru/hubman/dmx-executor/internal/device"
+)
+
+func TestLogDumpIsRateLimited(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	d := &virtualDevice{dump: device.VirtualDumpLog}
+	d.Logger = zap.New(core)
+
+	for i := 0; i < 10; i++ {
+		var frame [512]byte
+		frame[0] = byte(i)
+		if err := d.WriteFrameToDevice(frame); err != nil {
+			t.Fatalf("WriteFrameToDevice() error = %v", err)
+		}
+	}
+	if logs.Len() != 1 {
+		t.Fatalf("logged %d frames, want 1", logs.Len())
+	}
+
+	d.loggedAt = d.loggedAt.Add(-logDumpInterval)
+	if err := d.WriteFrameToDevice(d.frame); err != nil {
+		t.Fatalf("WriteFrameToDevice() error = %v", err)
+	}
+	if logs.Len() != 2 {
+		t.Fatalf("pending changed frame is not logged after interval")
+	}
+
+	d.loggedAt = d.loggedAt.Add(-logDumpInterval)
+	if err := d.WriteFrameToDevice(d.frame); err != nil {
+		t.Fatalf("WriteFrameToDevice() error = %v", err)
+	}
+	if logs.Len() != 2 {
+		t.Errorf("unchanged frame is logged")
+	}
+}